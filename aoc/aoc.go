@@ -0,0 +1,154 @@
+// Package aoc is a registry of every day's solutions, so that they can
+// be run, timed and checked from one place.
+package aoc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+// Solver solves one part of a day's puzzle given its input.
+type Solver func(r io.Reader) (string, error)
+
+type Day struct {
+	N            int
+	Part1, Part2 Solver // nil if the part can't be solved automatically
+}
+
+// Days returns the registered days in order.
+func Days() []Day {
+	return append([]Day(nil), days...)
+}
+
+func Lookup(n int) (Day, bool) {
+	for _, d := range days {
+		if d.N == n {
+			return d, true
+		}
+	}
+	return Day{}, false
+}
+
+// Part returns the solver for part 1 or 2.
+func (d Day) Part(part int) Solver {
+	switch part {
+	case 1:
+		return d.Part1
+	case 2:
+		return d.Part2
+	}
+	return nil
+}
+
+// Input returns the path of the day's puzzle input within the repository
+// rooted at root.
+func (d Day) Input(root string) string {
+	return filepath.Join(root, fmt.Sprintf("day%d", d.N), "input.txt")
+}
+
+// Output returns the path of the file holding the day's answers, one
+// part after the other.
+func (d Day) Output(root string) string {
+	return filepath.Join(root, fmt.Sprintf("day%d", d.N), "output.txt")
+}
+
+// Result is the answer to one part of a puzzle.
+type Result struct {
+	Day, Part int
+	Answer    string
+	Elapsed   time.Duration
+}
+
+// Run solves one part of the day using the input at path, timing only
+// the solver itself.
+func (d Day) Run(part int, path string) (Result, error) {
+	res := Result{Day: d.N, Part: part}
+	solve := d.Part(part)
+	if solve == nil {
+		return res, fmt.Errorf("day %d part %d: no solution", d.N, part)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+	start := time.Now()
+	res.Answer, err = solve(f)
+	res.Elapsed = time.Since(start)
+	if err != nil {
+		return res, fmt.Errorf("day %d part %d: %w", d.N, part, err)
+	}
+	return res, nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [flags] input_file\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+// Main solves both parts using the input file named on the command line
+// and prints the answers, one after the other. It's meant to be the
+// whole of a day's main function.
+func Main(part1, part2 Solver) {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+	for _, solve := range []Solver{part1, part2} {
+		if err := solveFile(solve, flag.Arg(0)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func solveFile(solve Solver, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	ans, err := solve(f)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ans)
+	return nil
+}
+
+// Visualizer records an animation of a day's solution given its input.
+type Visualizer func(r io.Reader) (*viz.Recorder, error)
+
+// MainViz is like Main, but with -viz FILE it also records an animation
+// and writes it to FILE, as described by viz.Recorder.WriteFile, and
+// -fps sets its frame rate.
+func MainViz(part1, part2 Solver, visualize Visualizer) {
+	path, fps := viz.Flags()
+	Main(part1, part2)
+	if *path == "" {
+		return
+	}
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	rec, err := visualize(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *fps > 0 {
+		rec.FPS = *fps
+	}
+	if err := rec.WriteFile(*path); err != nil {
+		log.Fatal(err)
+	}
+}
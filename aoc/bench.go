@@ -0,0 +1,144 @@
+package aoc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Timing summarizes several runs of one part of a puzzle.
+type Timing struct {
+	Day, Part int
+	Runs      int
+	Median    time.Duration
+	Min       time.Duration
+}
+
+// Bench solves one part of the day the given number of times using the
+// input at path. The input is read once up front, so only the solver is
+// timed.
+func (d Day) Bench(part int, path string, runs int) (Timing, error) {
+	t := Timing{Day: d.N, Part: part, Runs: runs}
+	solve := d.Part(part)
+	if solve == nil {
+		return t, fmt.Errorf("day %d part %d: no solution", d.N, part)
+	}
+	if runs < 1 {
+		return t, fmt.Errorf("can't bench %d runs", runs)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	elapsed := make([]time.Duration, runs)
+	for i := range elapsed {
+		start := time.Now()
+		_, err := solve(bytes.NewReader(data))
+		elapsed[i] = time.Since(start)
+		if err != nil {
+			return t, fmt.Errorf("day %d part %d: %w", d.N, part, err)
+		}
+	}
+	sort.Slice(elapsed, func(i, j int) bool { return elapsed[i] < elapsed[j] })
+	t.Min, t.Median = elapsed[0], elapsed[runs/2]
+	return t, nil
+}
+
+// BenchRun is one invocation of the benchmarks, as recorded in a history
+// file.
+type BenchRun struct {
+	Time    time.Time
+	Label   string `json:",omitempty"`
+	Go      string
+	Timings []Timing
+}
+
+// History is every recorded benchmark run, oldest first.
+type History []BenchRun
+
+// ReadHistory reads a history file, which holds one JSON-encoded run per
+// line. A missing file is an empty history.
+func ReadHistory(path string) (History, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var h History
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<20)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		var run BenchRun
+		if err := json.Unmarshal(s.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		h = append(h, run)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// AppendHistory adds a run to the end of a history file, creating it if
+// necessary.
+func AppendHistory(path string, run BenchRun) error {
+	b, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Last returns the most recent timing of the given part, since a run
+// may have covered only some of the days.
+func (h History) Last(day, part int) (Timing, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		for _, t := range h[i].Timings {
+			if t.Day == day && t.Part == part {
+				return t, true
+			}
+		}
+	}
+	return Timing{}, false
+}
+
+// Changes smaller than this are noise, however large they are relative
+// to the old time.
+const noiseFloor = 100 * time.Microsecond
+
+// Change compares a part's median time with an earlier one.
+type Change struct {
+	Old, New  Timing
+	Ratio     float64 // New.Median / Old.Median
+	Regressed bool
+}
+
+// Compare reports whether new is slower than old by more than the given
+// fraction of old, e.g. 0.1 for ten percent.
+func Compare(old, new Timing, threshold float64) Change {
+	c := Change{Old: old, New: new}
+	if old.Median > 0 {
+		c.Ratio = float64(new.Median) / float64(old.Median)
+	}
+	c.Regressed = c.Ratio > 1+threshold && new.Median-old.Median > noiseFloor
+	return c
+}
@@ -0,0 +1,94 @@
+package aoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Benchmarks every part of every day on its input.txt, e.g.
+//
+//	go test ./aoc -run '^$' -bench 'Days/day14/'
+func BenchmarkDays(b *testing.B) {
+	for _, d := range Days() {
+		data, err := os.ReadFile(d.Input(".."))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for part := 1; part <= 2; part++ {
+			solve := d.Part(part)
+			if solve == nil {
+				continue
+			}
+			name := "day" + strconv.Itoa(d.N) + "/part" + strconv.Itoa(part)
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := solve(bytes.NewReader(data)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.jsonl")
+	h, err := ReadHistory(path)
+	if err != nil || len(h) != 0 {
+		t.Fatalf("missing history: got %v, %v", h, err)
+	}
+	runs := History{
+		{Time: time.Unix(1, 0).UTC(), Go: "go1", Timings: []Timing{
+			{Day: 1, Part: 1, Runs: 3, Median: 5 * time.Millisecond, Min: 4 * time.Millisecond},
+			{Day: 1, Part: 2, Runs: 3, Median: 7 * time.Millisecond, Min: 6 * time.Millisecond},
+		}},
+		{Time: time.Unix(2, 0).UTC(), Label: "faster", Go: "go1", Timings: []Timing{
+			{Day: 1, Part: 1, Runs: 1, Median: 3 * time.Millisecond, Min: 3 * time.Millisecond},
+		}},
+	}
+	for _, run := range runs {
+		if err := AppendHistory(path, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, err = ReadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(h, runs) {
+		t.Fatalf("read %v, want %v", h, runs)
+	}
+	if got, _ := h.Last(1, 1); got.Median != 3*time.Millisecond {
+		t.Errorf("day 1 part 1: last median %s, want 3ms", got.Median)
+	}
+	if got, _ := h.Last(1, 2); got.Median != 7*time.Millisecond {
+		t.Errorf("day 1 part 2: last median %s, want 7ms", got.Median)
+	}
+	if _, ok := h.Last(2, 1); ok {
+		t.Error("day 2 part 1: found a timing that was never recorded")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		old, new  time.Duration
+		regressed bool
+	}{
+		{10 * time.Millisecond, 10 * time.Millisecond, false},
+		{10 * time.Millisecond, 5 * time.Millisecond, false},
+		{10 * time.Millisecond, 11 * time.Millisecond, false},
+		{10 * time.Millisecond, 12 * time.Millisecond, true},
+		{10 * time.Microsecond, 50 * time.Microsecond, false},
+		{0, time.Second, false},
+	} {
+		c := Compare(Timing{Median: tc.old}, Timing{Median: tc.new}, 0.1)
+		if c.Regressed != tc.regressed {
+			t.Errorf("%s -> %s: regressed = %t, want %t", tc.old, tc.new, c.Regressed, tc.regressed)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package aoc
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/day1"
+	"github.com/dhconnelly/advent-of-code-2019/day10"
+	"github.com/dhconnelly/advent-of-code-2019/day11"
+	"github.com/dhconnelly/advent-of-code-2019/day12"
+	"github.com/dhconnelly/advent-of-code-2019/day13"
+	"github.com/dhconnelly/advent-of-code-2019/day14"
+	"github.com/dhconnelly/advent-of-code-2019/day15"
+	"github.com/dhconnelly/advent-of-code-2019/day16"
+	"github.com/dhconnelly/advent-of-code-2019/day17"
+	"github.com/dhconnelly/advent-of-code-2019/day18"
+	"github.com/dhconnelly/advent-of-code-2019/day19"
+	"github.com/dhconnelly/advent-of-code-2019/day2"
+	"github.com/dhconnelly/advent-of-code-2019/day20"
+	"github.com/dhconnelly/advent-of-code-2019/day21"
+	"github.com/dhconnelly/advent-of-code-2019/day22"
+	"github.com/dhconnelly/advent-of-code-2019/day23"
+	"github.com/dhconnelly/advent-of-code-2019/day24"
+	"github.com/dhconnelly/advent-of-code-2019/day3"
+	"github.com/dhconnelly/advent-of-code-2019/day4"
+	"github.com/dhconnelly/advent-of-code-2019/day5"
+	"github.com/dhconnelly/advent-of-code-2019/day6"
+	"github.com/dhconnelly/advent-of-code-2019/day7"
+	"github.com/dhconnelly/advent-of-code-2019/day8"
+	"github.com/dhconnelly/advent-of-code-2019/day9"
+)
+
+// The registry, in order. Day 25 is an interactive game; see
+// day25/cmd/day25.
+var days = []Day{
+	{1, day1.Part1, day1.Part2},
+	{2, day2.Part1, day2.Part2},
+	{3, day3.Part1, day3.Part2},
+	{4, day4.Part1, day4.Part2},
+	{5, day5.Part1, day5.Part2},
+	{6, day6.Part1, day6.Part2},
+	{7, day7.Part1, day7.Part2},
+	{8, day8.Part1, day8.Part2},
+	{9, day9.Part1, day9.Part2},
+	{10, day10.Part1, day10.Part2},
+	{11, day11.Part1, day11.Part2},
+	{12, day12.Part1, day12.Part2},
+	{13, day13.Part1, day13.Part2},
+	{14, day14.Part1, day14.Part2},
+	{15, day15.Part1, day15.Part2},
+	{16, day16.Part1, day16.Part2},
+	{17, day17.Part1, day17.Part2},
+	{18, day18.Part1, day18.Part2},
+	{19, day19.Part1, day19.Part2},
+	{20, day20.Part1, day20.Part2},
+	{21, day21.Part1, day21.Part2},
+	{22, day22.Part1, day22.Part2},
+	{23, day23.Part1, day23.Part2},
+	{24, day24.Part1, day24.Part2},
+	{25, nil, nil},
+}
@@ -0,0 +1,52 @@
+package aoc
+
+import (
+	"flag"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite each day's output.txt with the current answers")
+
+// Days that take more than a few seconds, skipped with -short.
+var slow = map[int]bool{18: true}
+
+// Checks each day's answers against its output.txt.
+func TestGolden(t *testing.T) {
+	for _, d := range Days() {
+		d := d
+		if d.Part1 == nil || d.Part2 == nil {
+			continue
+		}
+		t.Run("day"+strconv.Itoa(d.N), func(t *testing.T) {
+			if slow[d.N] && testing.Short() {
+				t.Skip("slow")
+			}
+			t.Parallel()
+			var b strings.Builder
+			for part := 1; part <= 2; part++ {
+				res, err := d.Run(part, d.Input(".."))
+				if err != nil {
+					t.Fatal(err)
+				}
+				b.WriteString(res.Answer + "\n")
+			}
+			got := b.String()
+			if *update {
+				if err := ioutil.WriteFile(d.Output(".."), []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(d.Output(".."))
+			if err != nil {
+				t.Fatalf("%s (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("got:\n%swant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package breakout
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/dhconnelly/advent-of-code-2019/ints"
 	"github.com/gdamore/tcell"
 )
 
@@ -76,10 +78,28 @@ func Play(
 	screen tcell.Screen,
 	frameDelay time.Duration,
 	joystickInit JoystickPos,
+) (GameState, error) {
+	return Record(data, screen, frameDelay, joystickInit, nil)
+}
+
+// Record is like Play, but if s is non-nil it logs every joystick input
+// and screen output of the game to it.
+func Record(
+	data []int64,
+	screen tcell.Screen,
+	frameDelay time.Duration,
+	joystickInit JoystickPos,
+	s *intcode.Session,
 ) (GameState, error) {
 	in := make(chan int64)
 	defer close(in)
-	out := intcode.RunProgram(data, in)
+	var out <-chan int64
+	var wait func() error
+	if s != nil {
+		out, wait = intcode.Record(data, in, s)
+	} else {
+		out, wait = intcode.Start(data, in)
+	}
 	var events chan *tcell.EventKey
 	if screen != nil {
 		screen.Clear()
@@ -114,7 +134,7 @@ loop:
 
 		case x, ok := <-out:
 			if !ok {
-				break loop
+				return state, wait()
 			}
 
 			y, z := <-out, <-out
@@ -124,7 +144,7 @@ loop:
 				}
 			} else {
 				tile := TileId(z)
-				state.Tiles[geom.Pt2{int(x), int(y)}] = tile
+				state.Tiles[geom.Pt2{X: int(x), Y: int(y)}] = tile
 				if screen != nil {
 					draw(screen, int(x), int(y), tile)
 				}
@@ -134,3 +154,39 @@ loop:
 
 	return state, nil
 }
+
+// Autoplay runs the game without a screen or any delays, moving the
+// paddle toward the ball whenever the game asks for input, until the
+// program halts.
+func Autoplay(data []int64) (GameState, error) {
+	vm := intcode.NewMachine(data)
+	state := GameState{Tiles: ScreenTiles(make(map[geom.Pt2]TileId))}
+	var ball, paddle geom.Pt2
+	for {
+		running := vm.Run() != intcode.Halted
+		out := vm.Outputs()
+		if len(out)%3 != 0 {
+			return state, fmt.Errorf("bad output: %v", out)
+		}
+		for ; len(out) > 0; out = out[3:] {
+			x, y, z := out[0], out[1], out[2]
+			if x == -1 && y == 0 {
+				state.Score = int(z)
+				continue
+			}
+			p := geom.Pt2{X: int(x), Y: int(y)}
+			state.Tiles[p] = TileId(z)
+			switch TileId(z) {
+			case BALL:
+				ball = p
+			case PADDLE:
+				paddle = p
+			}
+		}
+		if !running {
+			return state, vm.Err()
+		}
+		state.Joystick = JoystickPos(ints.Sign(ball.X - paddle.X))
+		vm.Push(int64(state.Joystick))
+	}
+}
@@ -0,0 +1,50 @@
+#include <stdio.h>
+#include <stdlib.h>
+
+#include "parse.h"
+#include "vm.h"
+
+// runs the intcode program in the given file, reading its input from
+// stdin and writing its output to stdout, one value per line. exits with
+// status 1 if the vm fails or the program reads past the end of stdin.
+// this is the runner used by the conformance tests in ../intcode.
+int main(int argc, char* argv[]) {
+    if (argc != 2) {
+        fprintf(stderr, "usage: intrun file\n");
+        exit(2);
+    }
+    FILE* f = fopen(argv[1], "r");
+    if (f == NULL) {
+        perror("intrun");
+        exit(2);
+    }
+    static int64_t data[65536];
+    int len;
+    if ((len = parse_intcode(f, data, 65536)) < 0) {
+        perror("intrun");
+        exit(2);
+    }
+    fclose(f);
+    vm vm = make_vm(data, len);
+    do {
+        run(&vm);
+        switch (vm.state) {
+            case VM_ERROR:
+                fprintf(stderr, "vm error: %d\n", vm.error);
+                exit(1);
+            case VM_OUTPUT:
+                printf("%lld\n", vm.output);
+                break;
+            case VM_INPUT:
+                if (scanf("%lld", &vm.input) != 1) {
+                    fprintf(stderr, "out of input\n");
+                    exit(1);
+                }
+                break;
+            case VM_RUNNING:
+                break;
+            case VM_HALTED:
+                break;
+        }
+    } while (vm.state != VM_HALTED);
+}
@@ -114,8 +114,9 @@ static void print_instr(int pc, instr instr) {
 
 static void step(vm* vm) {
     if (vm->state == VM_INPUT) {
-        instr prev_instr = parse_instr(*table_get(&vm->mem, vm->pc));
+        instr prev_instr = parse_instr(get_mem(vm, vm->pc));
         int dest = eval_dest(vm, prev_instr.modes[0], vm->pc + 1);
+        if (vm->state == VM_ERROR) return;
         set_mem(vm, dest, vm->input);
         vm->pc += 2;
         vm->state = VM_RUNNING;
@@ -129,7 +130,7 @@ static void step(vm* vm) {
         return;
     }
 
-    instr instr = parse_instr(*table_get(&vm->mem, vm->pc));
+    instr instr = parse_instr(get_mem(vm, vm->pc));
     if (vm->trace) print_instr(vm->pc, instr);
     switch (instr.op) {
         case ADD: {
@@ -159,6 +160,7 @@ static void step(vm* vm) {
 
         case OUT: {
             int64_t src = eval_arg(vm, instr.modes[0], vm->pc + 1);
+            if (vm->state == VM_ERROR) return;
             vm->output = src;
             vm->pc += 2;
             vm->state = VM_OUTPUT;
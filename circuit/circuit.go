@@ -0,0 +1,88 @@
+// Package circuit wires intcode machines together into arbitrary
+// topologies: series, feedback loops, fan-out and fan-in.
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+type Node int
+
+type node struct {
+	prog []int64
+	init []int64
+}
+
+type Circuit struct {
+	nodes []node
+	edges [][2]Node
+}
+
+func New() *Circuit {
+	return &Circuit{}
+}
+
+// Add places a machine running prog in the circuit. The values in init
+// (e.g. a phase setting) are fed to the machine before any signals
+// arriving from upstream nodes.
+func (c *Circuit) Add(prog []int64, init ...int64) Node {
+	c.nodes = append(c.nodes, node{prog: prog, init: init})
+	return Node(len(c.nodes) - 1)
+}
+
+// Connect sends every output of from to the input of to. A node may
+// have any number of downstream and upstream connections.
+func (c *Circuit) Connect(from, to Node) {
+	c.edges = append(c.edges, [2]Node{from, to})
+}
+
+func (c *Circuit) valid(n Node) bool {
+	return n >= 0 && int(n) < len(c.nodes)
+}
+
+type Result struct {
+	Outputs [][]int64
+}
+
+// Signal returns the last value written by the given node.
+func (r Result) Signal(n Node) (int64, bool) {
+	if n < 0 || int(n) >= len(r.Outputs) || len(r.Outputs[n]) == 0 {
+		return 0, false
+	}
+	out := r.Outputs[n]
+	return out[len(out)-1], true
+}
+
+// Run runs every machine in the circuit until all of them halt, using
+// an intcode.Scheduler so that signals from several upstream nodes are
+// always interleaved in the same order. It returns the error of the
+// first machine to stop with one, or an error wrapping
+// intcode.ErrDeadlock if some machine waits for input that will never
+// arrive.
+func (c *Circuit) Run() (Result, error) {
+	outs := make([][]Node, len(c.nodes))
+	for _, e := range c.edges {
+		from, to := e[0], e[1]
+		if !c.valid(from) || !c.valid(to) {
+			return Result{}, fmt.Errorf("bad connection: %d -> %d", from, to)
+		}
+		outs[from] = append(outs[from], to)
+	}
+
+	vms := make([]*intcode.Machine, len(c.nodes))
+	for i, nd := range c.nodes {
+		vms[i] = intcode.NewMachine(nd.prog)
+		vms[i].Push(nd.init...)
+	}
+	res := Result{Outputs: make([][]int64, len(c.nodes))}
+	s := intcode.NewScheduler(vms...)
+	s.Output = func(i int, out []int64) {
+		res.Outputs[i] = append(res.Outputs[i], out...)
+		for _, to := range outs[i] {
+			vms[to].Push(out...)
+		}
+	}
+	return res, s.Run()
+}
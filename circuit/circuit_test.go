@@ -0,0 +1,119 @@
+package circuit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+var (
+	seriesProg = []int64{3, 15, 3, 16, 1002, 16, 10, 16, 1, 16, 15, 15, 4, 15, 99, 0, 0}
+	loopProg   = []int64{
+		3, 26, 1001, 26, -4, 26, 3, 27, 1002, 27, 2, 27, 1, 27, 26, 27, 4, 27,
+		1001, 28, -1, 28, 1005, 28, 6, 99, 0, 0, 5,
+	}
+	double = []int64{3, 9, 1002, 9, 2, 9, 4, 9, 99, 0}
+	sum    = []int64{3, 13, 3, 14, 1, 13, 14, 15, 4, 15, 99, 0, 0, 0, 0, 0}
+)
+
+func TestSignal(t *testing.T) {
+	for _, tc := range []struct {
+		prog   []int64
+		top    Topology
+		phases []int64
+		want   int64
+	}{
+		{seriesProg, Series, []int64{4, 3, 2, 1, 0}, 43210},
+		{loopProg, Loop, []int64{9, 8, 7, 6, 5}, 139629729},
+	} {
+		got, err := Signal(tc.prog, tc.top, tc.phases, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Signal(%v) = %d, want %d", tc.phases, got, tc.want)
+		}
+	}
+}
+
+func TestMaxSignal(t *testing.T) {
+	for _, tc := range []struct {
+		prog   []int64
+		top    Topology
+		phases []int64
+		best   []int64
+		want   int64
+	}{
+		{seriesProg, Series, []int64{0, 1, 2, 3, 4}, []int64{4, 3, 2, 1, 0}, 43210},
+		{loopProg, Loop, []int64{5, 6, 7, 8, 9}, []int64{9, 8, 7, 6, 5}, 139629729},
+	} {
+		best, got, err := MaxSignal(tc.prog, tc.top, tc.phases, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want || !reflect.DeepEqual(best, tc.best) {
+			t.Errorf("MaxSignal(%v) = %v, %d, want %v, %d", tc.phases, best, got, tc.best, tc.want)
+		}
+	}
+}
+
+func TestFanOutFanIn(t *testing.T) {
+	c := New()
+	a := c.Add(double, 3)
+	b := c.Add(double)
+	d := c.Add(double)
+	s := c.Add(sum)
+	c.Connect(a, b)
+	c.Connect(a, d)
+	c.Connect(b, s)
+	c.Connect(d, s)
+	res, err := c.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := res.Signal(s); !ok || got != 24 {
+		t.Errorf("Signal(%d) = %d, %t, want 24", s, got, ok)
+	}
+	if got := res.Outputs[a]; !reflect.DeepEqual(got, []int64{6}) {
+		t.Errorf("Outputs[%d] = %v, want [6]", a, got)
+	}
+}
+
+func TestBadConnection(t *testing.T) {
+	c := New()
+	c.Connect(c.Add(double, 1), 7)
+	if _, err := c.Run(); err == nil {
+		t.Error("Run() succeeded with a dangling connection")
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	perms := Permutations([]int64{0, 1, 2})
+	want := [][]int64{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	if !reflect.DeepEqual(perms, want) {
+		t.Errorf("Permutations = %v, want %v", perms, want)
+	}
+}
+
+func TestDeadlock(t *testing.T) {
+	c := New()
+	a := c.Add(double)
+	b := c.Add(double)
+	c.Connect(a, b)
+	c.Connect(b, a)
+	if _, err := c.Run(); !errors.Is(err, intcode.ErrDeadlock) {
+		t.Errorf("Run() = %v, want %v", err, intcode.ErrDeadlock)
+	}
+}
+
+func TestBadNode(t *testing.T) {
+	c := New()
+	a := c.Add([]int64{104, 5, 99})
+	b := c.Add([]int64{3, 0, 77})
+	c.Connect(a, b)
+	if _, err := c.Run(); !errors.Is(err, intcode.ErrBadOpcode) {
+		t.Errorf("Run() = %v, want %v", err, intcode.ErrBadOpcode)
+	}
+}
@@ -0,0 +1,119 @@
+package circuit
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Topology builds a circuit of copies of prog configured with the given
+// phase settings and returns it along with the node whose last output
+// is the circuit's signal.
+type Topology func(prog []int64, phases []int64, input int64) (*Circuit, Node)
+
+// Series chains one machine per phase setting, feeding input to the
+// first and each machine's output to the next.
+func Series(prog []int64, phases []int64, input int64) (*Circuit, Node) {
+	c := New()
+	prev := Node(-1)
+	for i, phase := range phases {
+		var n Node
+		if i == 0 {
+			n = c.Add(prog, phase, input)
+		} else {
+			n = c.Add(prog, phase)
+			c.Connect(prev, n)
+		}
+		prev = n
+	}
+	return c, prev
+}
+
+// Loop is like Series, but the last machine's output is also fed back
+// into the first.
+func Loop(prog []int64, phases []int64, input int64) (*Circuit, Node) {
+	c, last := Series(prog, phases, input)
+	if len(phases) > 0 {
+		c.Connect(last, 0)
+	}
+	return c, last
+}
+
+func permutationsRec(nums []int64, used []bool, cur []int64, perms [][]int64) [][]int64 {
+	if len(cur) == len(nums) {
+		return append(perms, append([]int64(nil), cur...))
+	}
+	for i, num := range nums {
+		if !used[i] {
+			used[i] = true
+			perms = permutationsRec(nums, used, append(cur, num), perms)
+			used[i] = false
+		}
+	}
+	return perms
+}
+
+// Permutations returns every ordering of nums.
+func Permutations(nums []int64) [][]int64 {
+	return permutationsRec(nums, make([]bool, len(nums)), nil, nil)
+}
+
+// Signal builds the circuit for the given phases and runs it to
+// completion, returning the signal.
+func Signal(prog []int64, top Topology, phases []int64, input int64) (int64, error) {
+	c, out := top(prog, phases, input)
+	res, err := c.Run()
+	if err != nil {
+		return 0, err
+	}
+	sig, ok := res.Signal(out)
+	if !ok {
+		return 0, fmt.Errorf("no signal for phases %v", phases)
+	}
+	return sig, nil
+}
+
+// Search evaluates every candidate in parallel and returns the one
+// yielding the largest signal. Ties go to the earliest candidate.
+func Search(candidates [][]int64, eval func(phases []int64) (int64, error)) ([]int64, int64, error) {
+	if len(candidates) == 0 {
+		return nil, 0, fmt.Errorf("no candidates")
+	}
+	sigs := make([]int64, len(candidates))
+	errs := make([]error, len(candidates))
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				sigs[i], errs[i] = eval(candidates[i])
+			}
+		}()
+	}
+	for i := range candidates {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+
+	best := 0
+	for i := range candidates {
+		if errs[i] != nil {
+			return nil, 0, errs[i]
+		}
+		if sigs[i] > sigs[best] {
+			best = i
+		}
+	}
+	return candidates[best], sigs[best], nil
+}
+
+// MaxSignal searches every permutation of phases for the one yielding
+// the largest signal when prog is wired up according to top.
+func MaxSignal(prog []int64, top Topology, phases []int64, input int64) ([]int64, int64, error) {
+	return Search(Permutations(phases), func(p []int64) (int64, error) {
+		return Signal(prog, top, p, input)
+	})
+}
@@ -0,0 +1,231 @@
+// Command aoc runs the Advent of Code solutions.
+//
+// Usage:
+//
+//	aoc run (DAY | all) [--part N] [--input PATH] [--root DIR]
+//	aoc bench (DAY | all) [--part N] [--input PATH] [--root DIR]
+//		[--runs N] [--history FILE] [--threshold F] [--label TEXT]
+//
+// Without --part, both parts are run. The input defaults to the day's
+// input.txt under the repository root, which defaults to the current
+// directory.
+//
+// The bench command runs each part several times and reports the median
+// and fastest times. With --history, it compares them against the most
+// recent run recorded in that file, flags any part whose median got
+// slower by more than the threshold, and then records this run. It exits
+// with status 1 if anything regressed.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc run (DAY | all) [--part N] [--input PATH] [--root DIR]")
+	fmt.Fprintln(os.Stderr, "       aoc bench (DAY | all) [--part N] [--input PATH] [--root DIR]")
+	fmt.Fprintln(os.Stderr, "                 [--runs N] [--history FILE] [--threshold F] [--label TEXT]")
+	os.Exit(2)
+}
+
+func printResult(res aoc.Result) {
+	elapsed := res.Elapsed.Round(time.Microsecond)
+	if strings.Contains(res.Answer, "\n") {
+		fmt.Printf("day %d part %d (%s):\n%s\n", res.Day, res.Part, elapsed, res.Answer)
+	} else {
+		fmt.Printf("day %d part %d: %s (%s)\n", res.Day, res.Part, res.Answer, elapsed)
+	}
+}
+
+type config struct {
+	part        int
+	input, root string
+
+	// for bench
+	runs      int
+	history   string
+	threshold float64
+	label     string
+}
+
+func (cfg config) parts() []int {
+	if cfg.part != 0 {
+		return []int{cfg.part}
+	}
+	return []int{1, 2}
+}
+
+func (cfg config) path(d aoc.Day) string {
+	if cfg.input != "" {
+		return cfg.input
+	}
+	return d.Input(cfg.root)
+}
+
+// Runs the requested parts of the day and returns the time taken.
+func run(d aoc.Day, cfg config) (time.Duration, error) {
+	var total time.Duration
+	for _, part := range cfg.parts() {
+		res, err := d.Run(part, cfg.path(d))
+		if err != nil {
+			return total, err
+		}
+		printResult(res)
+		total += res.Elapsed
+	}
+	return total, nil
+}
+
+func runAll(cfg config) error {
+	var total time.Duration
+	var errs []string
+	for _, d := range aoc.Days() {
+		if d.Part1 == nil && d.Part2 == nil {
+			fmt.Printf("day %d: skipped\n", d.N)
+			continue
+		}
+		elapsed, err := run(d, cfg)
+		total += elapsed
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			errs = append(errs, strconv.Itoa(d.N))
+		}
+	}
+	fmt.Printf("total: %s\n", total.Round(time.Microsecond))
+	if len(errs) > 0 {
+		return fmt.Errorf("failed days: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func formatChange(c aoc.Change) string {
+	s := fmt.Sprintf("%+.1f%%", 100*(c.Ratio-1))
+	if c.Regressed {
+		s += " REGRESSED"
+	}
+	return s
+}
+
+// Benchmarks the requested parts of the given days and prints a table of
+// their timings, compared with the history if there is one.
+func bench(days []aoc.Day, cfg config) error {
+	var hist aoc.History
+	if cfg.history != "" {
+		var err error
+		if hist, err = aoc.ReadHistory(cfg.history); err != nil {
+			return err
+		}
+	}
+	run := aoc.BenchRun{Time: time.Now().UTC(), Label: cfg.label, Go: runtime.Version()}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "day\tpart\tmedian\tmin\tprevious\tchange\t")
+	var regressed []string
+	for _, d := range days {
+		if d.Part1 == nil && d.Part2 == nil {
+			continue
+		}
+		for _, part := range cfg.parts() {
+			t, err := d.Bench(part, cfg.path(d), cfg.runs)
+			if err != nil {
+				w.Flush()
+				return err
+			}
+			run.Timings = append(run.Timings, t)
+			prev, change := "-", "-"
+			if old, ok := hist.Last(d.N, part); ok {
+				c := aoc.Compare(old, t, cfg.threshold)
+				prev, change = old.Median.Round(time.Microsecond).String(), formatChange(c)
+				if c.Regressed {
+					regressed = append(regressed, fmt.Sprintf("%d.%d", d.N, part))
+				}
+			}
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t\n", d.N, part,
+				t.Median.Round(time.Microsecond), t.Min.Round(time.Microsecond), prev, change)
+		}
+	}
+	w.Flush()
+	if cfg.history != "" {
+		if err := aoc.AppendHistory(cfg.history, run); err != nil {
+			return err
+		}
+	}
+	if len(regressed) > 0 {
+		return fmt.Errorf("regressed: %s", strings.Join(regressed, ", "))
+	}
+	return nil
+}
+
+// Parses the flags, which may come before or after the day.
+func parse(cmd string, args []string) (string, config) {
+	var cfg config
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = usage
+	fs.IntVar(&cfg.part, "part", 0, "run only this part (1 or 2)")
+	fs.StringVar(&cfg.input, "input", "", "read the puzzle input from this file")
+	fs.StringVar(&cfg.root, "root", ".", "find inputs in this repository")
+	if cmd == "bench" {
+		fs.IntVar(&cfg.runs, "runs", 5, "run each part this many times")
+		fs.StringVar(&cfg.history, "history", "", "compare with and record to this file")
+		fs.Float64Var(&cfg.threshold, "threshold", 0.1, "flag medians slower by more than this fraction")
+		fs.StringVar(&cfg.label, "label", "", "describe this run in the history")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		usage()
+	}
+	day := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 || cfg.part < 0 || cfg.part > 2 {
+		usage()
+	}
+	if cmd == "bench" && (cfg.runs < 1 || cfg.threshold < 0) {
+		usage()
+	}
+	return day, cfg
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "run" && os.Args[1] != "bench" {
+		usage()
+	}
+	cmd := os.Args[1]
+	day, cfg := parse(cmd, os.Args[2:])
+	var days []aoc.Day
+	var err error
+	if day == "all" {
+		if cfg.input != "" {
+			err = errors.New("can't use --input with all")
+		}
+		days = aoc.Days()
+	} else {
+		n, convErr := strconv.Atoi(day)
+		d, ok := aoc.Lookup(n)
+		if convErr != nil || !ok {
+			err = fmt.Errorf("no such day: %s", day)
+		}
+		days = []aoc.Day{d}
+	}
+	switch {
+	case err != nil:
+	case cmd == "bench":
+		err = bench(days, cfg)
+	case day == "all":
+		err = runAll(cfg)
+	default:
+		_, err = run(days[0], cfg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
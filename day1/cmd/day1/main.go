@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day1"
+)
+
+func main() {
+	aoc.Main(day1.Part1, day1.Part2)
+}
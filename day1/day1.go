@@ -1,12 +1,11 @@
-package main
+// Package day1 computes the fuel needed to launch a spacecraft.
+package day1
 
 import (
-	"bufio"
-	"fmt"
 	"io"
-	"log"
-	"os"
 	"strconv"
+
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 func fuelForMass(mass int) int {
@@ -26,49 +25,51 @@ func fuelSum(mass int) int {
 	return sum
 }
 
-func solvePart1(masses <-chan int) int {
+func solvePart1(masses []int) int {
 	sum := 0
-	for mass := range masses {
+	for _, mass := range masses {
 		sum += fuelForMass(mass)
 	}
 	return sum
 }
 
-func solvePart2(masses <-chan int) int {
+func solvePart2(masses []int) int {
 	sum := 0
-	for mass := range masses {
+	for _, mass := range masses {
 		sum += fuelSum(mass)
 	}
 	return sum
 }
 
-func scanLines(r io.Reader) <-chan int {
-	ch := make(chan int)
-	go func() {
-		scan := bufio.NewScanner(r)
-		for scan.Scan() {
-			i, err := strconv.Atoi(scan.Text())
-			if err != nil {
-				log.Fatal(err)
-			}
-			ch <- i
-		}
-		if err := scan.Err(); err != nil {
-			log.Fatal(err)
+func readMasses(r io.Reader) ([]int, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	masses := make([]int, len(lines))
+	for i, line := range lines {
+		if masses[i], err = line.Int(); err != nil {
+			return nil, err
 		}
-		close(ch)
-	}()
-	return ch
+	}
+	return masses, nil
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part1 returns the fuel needed for the modules whose masses are listed
+// in r, one per line.
+func Part1(r io.Reader) (string, error) {
+	masses, err := readMasses(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	defer f.Close()
+	return strconv.Itoa(solvePart1(masses)), nil
+}
 
-	fmt.Println(solvePart1(scanLines(f)))
-	f.Seek(0, 0)
-	fmt.Println(solvePart2(scanLines(f)))
+// Part2 is like Part1, but also counts the fuel needed for the fuel.
+func Part2(r io.Reader) (string, error) {
+	masses, err := readMasses(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(solvePart2(masses)), nil
 }
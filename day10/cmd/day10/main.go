@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day10"
+)
+
+func main() {
+	aoc.Main(day10.Part1, day10.Part2)
+}
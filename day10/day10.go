@@ -1,15 +1,14 @@
-package main
+// Package day10 finds the best asteroid for a monitoring station and the
+// order in which its laser vaporizes the others.
+package day10
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"math"
-	"os"
-	"sort"
+	"io"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 type grid struct {
@@ -17,85 +16,51 @@ type grid struct {
 	points        map[geom.Pt2]bool
 }
 
-func readGrid(path string) grid {
-	txt, err := ioutil.ReadFile(path)
+func readGrid(r io.Reader) (grid, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatal(err)
+		return grid{}, err
 	}
-	txt = bytes.TrimSpace(txt)
-	lines := bytes.Split(txt, []byte("\n"))
-	g := grid{len(lines[0]), len(lines), make(map[geom.Pt2]bool)}
+	for len(lines) > 0 && lines[len(lines)-1].Text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return grid{}, fmt.Errorf("empty grid")
+	}
+	g := grid{len(lines[0].Text), len(lines), make(map[geom.Pt2]bool)}
 	for i, line := range lines {
-		for j, ch := range line {
-			if ch == '#' {
-				g.points[geom.Pt2{j, i}] = true
-			}
+		if len(line.Text) != g.width {
+			return grid{}, line.Errorf(0, "row has %d cells, want %d", len(line.Text), g.width)
 		}
-	}
-	return g
-}
-
-func inBounds(g grid, p geom.Pt2) bool {
-	return p.X < g.width && p.Y < g.height && p.X >= 0 && p.Y >= 0
-}
-
-func allStepsFrom(g grid, from geom.Pt2, dx, dy int) []geom.Pt2 {
-	var steps []geom.Pt2
-	reachable := make(map[geom.Pt2]bool)
-	for i := 0; i < g.height; i++ {
-		for j := 0; j < g.width; j++ {
-			add := false
-			d := geom.Pt2{dx * j, dy * i}
-			if d == geom.Zero2 {
-				continue
-			}
-			for p := from.Add(d); inBounds(g, p); p = p.Add(d) {
-				if !reachable[p] {
-					add = true
-					reachable[p] = true
-				}
-			}
-			if add {
-				steps = append(steps, d)
+		for j, ch := range line.Text {
+			switch ch {
+			case '#':
+				g.points[geom.Pt2{X: j, Y: i}] = true
+			case '.':
+			default:
+				return grid{}, line.Errorf(j, "bad cell %q", ch)
 			}
 		}
 	}
-	return steps
+	return g, nil
 }
 
-func allSteps(g grid) []geom.Pt2 {
-	var steps []geom.Pt2
-	steps = append(steps, allStepsFrom(g, geom.Pt2{0, g.height}, 1, -1)...)
-	steps = append(steps, allStepsFrom(g, geom.Pt2{0, 0}, 1, 1)...)
-	steps = append(steps, allStepsFrom(g, geom.Pt2{g.width, g.height}, -1, -1)...)
-	steps = append(steps, allStepsFrom(g, geom.Pt2{g.width, 0}, -1, 1)...)
-	return steps
-}
-
-func visit(visible map[geom.Pt2]int, g grid, p geom.Pt2, steps []geom.Pt2) {
-	for _, d := range steps {
-		var cur geom.Pt2
-		for cur = p.Add(d); inBounds(g, cur) && !g.points[cur]; cur = cur.Add(d) {
-		}
-		if g.points[cur] {
-			visible[p]++
+func (g grid) asteroids() []geom.Pt2 {
+	var ps []geom.Pt2
+	for p, ok := range g.points {
+		if ok {
+			ps = append(ps, p)
 		}
 	}
+	return ps
 }
 
-func countVisible(g grid, steps []geom.Pt2) map[geom.Pt2]int {
-	counts := make(map[geom.Pt2]int)
-	for p, _ := range g.points {
-		visit(counts, g, p, steps)
-	}
-	return counts
-}
-
-func bestPoint(g grid, counts map[geom.Pt2]int) (geom.Pt2, int) {
+func bestPoint(g grid) (geom.Pt2, int) {
 	var best geom.Pt2
 	count := 0
-	for p, _ := range g.points {
-		if c := counts[p]; c > count {
+	ps := g.asteroids()
+	for _, p := range ps {
+		if c := len(geom.Screen.Visible(p, ps)); c > count {
 			best = p
 			count = c
 		}
@@ -103,72 +68,40 @@ func bestPoint(g grid, counts map[geom.Pt2]int) (geom.Pt2, int) {
 	return best, count
 }
 
-type byAngleFrom struct {
-	p  geom.Pt2
-	ps []geom.Pt2
-}
-
-func (points byAngleFrom) Len() int {
-	return len(points.ps)
-}
-
-func angle(p1, p2 geom.Pt2) float64 {
-	return math.Atan2(float64(p2.Y-p1.Y), float64(p2.X-p1.X))
-}
-
-func (points byAngleFrom) Less(i, j int) bool {
-	to1, to2 := points.ps[i], points.ps[j]
-	a1 := angle(points.p, to1)
-	a2 := angle(points.p, to2)
-	return a1 <= a2
-}
-
-func (points byAngleFrom) Swap(i, j int) {
-	points.ps[j], points.ps[i] = points.ps[i], points.ps[j]
-}
-
-func reachableFrom(g grid, from geom.Pt2, steps []geom.Pt2) []geom.Pt2 {
-	var to []geom.Pt2
-	for _, d := range steps {
-		var p geom.Pt2
-		for p = from.Add(d); inBounds(g, p) && !g.points[p]; p = p.Add(d) {
-		}
-		if g.points[p] {
-			to = append(to, p)
+func vaporizeAll(g grid, from geom.Pt2) []geom.Pt2 {
+	var ordered []geom.Pt2
+	for ps := g.asteroids(); len(ps) > 1; ps = g.asteroids() {
+		for _, p := range geom.Screen.Visible(from, ps) {
+			g.points[p] = false
+			ordered = append(ordered, p)
 		}
 	}
-	sort.Sort(byAngleFrom{from, to})
-	var j int
-	for j = 0; j < len(to) && angle(from, to[j]) < -math.Pi/2.0; j++ {
-	}
-	ordered := make([]geom.Pt2, len(to))
-	for i := 0; i < len(to); i++ {
-		ix := (j + i) % len(to)
-		ordered[i] = to[ix]
-	}
 	return ordered
 }
 
-func vaporizeAll(g grid, from geom.Pt2, steps []geom.Pt2) []geom.Pt2 {
-	ordered := make([]geom.Pt2, len(g.points))
-	for vaporized := 0; vaporized < len(g.points)-1; {
-		toVaporize := reachableFrom(g, from, steps)
-		for _, p := range toVaporize {
-			g.points[p] = false
-			ordered[vaporized] = p
-			vaporized++
-		}
+// Part1 returns the number of asteroids visible from the best location
+// for a monitoring station.
+func Part1(r io.Reader) (string, error) {
+	g, err := readGrid(r)
+	if err != nil {
+		return "", err
 	}
-	return ordered
+	_, count := bestPoint(g)
+	return strconv.Itoa(count), nil
 }
 
-func main() {
-	g := readGrid(os.Args[1])
-	steps := allSteps(g)
-	counts := countVisible(g, steps)
-	best, count := bestPoint(g, counts)
-	fmt.Println(count)
-	vaporized := vaporizeAll(g, best, steps)
+// Part2 returns 100*X + Y for the 200th asteroid vaporized from the
+// monitoring station.
+func Part2(r io.Reader) (string, error) {
+	g, err := readGrid(r)
+	if err != nil {
+		return "", err
+	}
+	best, _ := bestPoint(g)
+	vaporized := vaporizeAll(g, best)
+	if len(vaporized) < 200 {
+		return "", fmt.Errorf("only %d asteroids vaporized", len(vaporized))
+	}
 	winPt := vaporized[199]
-	fmt.Println(winPt.X*100 + winPt.Y)
+	return strconv.Itoa(winPt.X*100 + winPt.Y), nil
 }
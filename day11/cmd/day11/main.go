@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day11"
+)
+
+func main() {
+	aoc.MainViz(day11.Part1, day11.Part2, day11.Visualize)
+}
@@ -1,13 +1,16 @@
-package main
+// Package day11 runs the hull painting robot.
+package day11
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
 	"github.com/dhconnelly/advent-of-code-2019/ints"
-	"log"
-	"math"
-	"os"
 )
 
 type color int64
@@ -24,49 +27,26 @@ const (
 	TURN_RIGHT
 )
 
-type orientation rune
-
-const (
-	LEFT orientation = iota
-	UP
-	RIGHT
-	DOWN
-)
-
-func turn(cur orientation, dir direction) orientation {
+func turn(h geom.Heading, dir direction) (geom.Heading, error) {
 	switch dir {
 	case TURN_LEFT:
-		if cur == LEFT {
-			return DOWN
-		}
-		return cur - 1
+		return h.TurnLeft(), nil
 	case TURN_RIGHT:
-		return (cur + 1) % 4
+		return h.TurnRight(), nil
 	}
-	log.Fatalf("bad dir: %d", dir)
-	return -1
-}
-
-var diffs = map[orientation]geom.Pt2{
-	LEFT:  geom.Pt2{-1, 0},
-	UP:    geom.Pt2{0, 1},
-	RIGHT: geom.Pt2{1, 0},
-	DOWN:  geom.Pt2{0, -1},
-}
-
-func move(cur geom.Pt2, o orientation) geom.Pt2 {
-	return cur.Add(diffs[o])
+	return h, fmt.Errorf("bad dir: %d", dir)
 }
 
 type grid map[geom.Pt2]color
 
-func run(data []int64, initial color) grid {
+// Runs the robot, calling moved, if it isn't nil, after each panel is
+// painted and the robot moves on.
+func run(data []int64, initial color, moved func(g grid, h geom.Heading)) (grid, error) {
 	in := make(chan int64)
-	out := intcode.RunProgram(data, in)
+	out, wait := intcode.Start(data, in)
 	g := grid(make(map[geom.Pt2]color))
-	p := geom.Zero2
-	g[p] = initial
-	o := UP
+	h := geom.Heading{Dir: geom.Up}
+	g[h.Pos] = initial
 loop:
 	for {
 		select {
@@ -74,17 +54,29 @@ loop:
 			if !ok {
 				break loop
 			}
-			g[p] = color(c)
-			dir := direction(<-out)
-			o = turn(o, dir)
-			p = move(p, o)
-		case in <- int64(g[p]):
+			g[h.Pos] = color(c)
+			dir, ok := <-out
+			if !ok {
+				break loop
+			}
+			next, err := turn(h, direction(dir))
+			if err != nil {
+				return nil, err
+			}
+			h = next.Forward(1)
+			if moved != nil {
+				moved(g, h)
+			}
+		case in <- int64(g[h.Pos]):
 		}
 	}
-	return g
+	if err := wait(); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
-func printGrid(g grid) {
+func printGrid(w io.Writer, g grid) {
 	minX, minY := math.MaxInt64, math.MaxInt64
 	maxX, maxY := math.MinInt64, math.MinInt64
 	for p, _ := range g {
@@ -93,25 +85,44 @@ func printGrid(g grid) {
 	}
 	for row := maxY; row >= minY; row-- {
 		for col := minX; col <= maxX; col++ {
-			p := geom.Pt2{col, row}
+			p := geom.Pt2{X: col, Y: row}
 			switch g[p] {
 			case BLACK:
-				fmt.Print(" ")
+				fmt.Fprint(w, " ")
 			case WHITE:
-				fmt.Print("X")
+				fmt.Fprint(w, "X")
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+// Part1 returns the number of panels the robot paints at least once,
+// starting on a black panel.
+func Part1(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	g, err := run(data, BLACK, nil)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(g)), nil
+}
+
+// Part2 returns the registration identifier the robot paints when it
+// starts on a white panel, drawn as text.
+func Part2(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	g, err := run(data, WHITE, nil)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	g := run(data, BLACK)
-	fmt.Println(len(g))
-	g = run(data, WHITE)
-	printGrid(g)
+	var b strings.Builder
+	printGrid(&b, g)
+	return strings.TrimSuffix(b.String(), "\n"), nil
 }
@@ -0,0 +1,46 @@
+package day11
+
+import (
+	imagecolor "image/color"
+	"io"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+const robot = 2
+
+// Indexed by color, and then the robot.
+var palette = imagecolor.Palette{
+	imagecolor.Black,
+	imagecolor.White,
+	imagecolor.RGBA{255, 64, 64, 255},
+}
+
+// Visualize records the robot painting the registration identifier, one
+// frame per panel painted.
+func Visualize(r io.Reader) (*viz.Recorder, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	rec := viz.NewRecorder(palette)
+	rec.Scale, rec.FPS = 10, 30
+	panels := geom.NewSparse[color]()
+	_, err = run(data, WHITE, func(g grid, h geom.Heading) {
+		for p, c := range g {
+			panels.Set(p, c)
+		}
+		panels.Set(h.Pos, g[h.Pos])
+		f := viz.Snapshot(panels, geom.Cartesian, func(c color, ok bool) uint8 {
+			return uint8(c)
+		})
+		f.Set(geom.Pt2{X: h.Pos.X, Y: -h.Pos.Y}, robot)
+		rec.Add(f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
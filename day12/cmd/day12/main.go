@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day12"
+)
+
+func main() {
+	aoc.Main(day12.Part1, day12.Part2)
+}
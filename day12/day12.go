@@ -0,0 +1,132 @@
+// Package day12 simulates the motion of Jupiter's moons.
+package day12
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dhconnelly/advent-of-code-2019/input"
+	"github.com/dhconnelly/advent-of-code-2019/ints"
+)
+
+type state struct {
+	px, py, pz, vx, vy, vz [4]int64
+}
+
+type moon struct {
+	X, Y, Z int64
+}
+
+var moonParser = input.MustParser[moon](`<x=(-?\d+), y=(-?\d+), z=(-?\d+)>`)
+
+func readState(r io.Reader) (state, error) {
+	var s state
+	moons, err := moonParser.ParseLines(r)
+	if err != nil {
+		return s, err
+	}
+	if len(moons) != len(s.px) {
+		return s, fmt.Errorf("got %d moons, want %d", len(moons), len(s.px))
+	}
+	for i, m := range moons {
+		s.px[i], s.py[i], s.pz[i] = m.X, m.Y, m.Z
+	}
+	return s, nil
+}
+
+func applyGravity(px, vx *[4]int64) {
+	for i := 0; i < len(px)-1; i++ {
+		for j := i + 1; j < len(px); j++ {
+			if px[i] < px[j] {
+				vx[i] += 1
+				vx[j] -= 1
+			} else if px[i] > px[j] {
+				vx[i] -= 1
+				vx[j] += 1
+			}
+		}
+	}
+}
+
+func applyVelocity(px, vx *[4]int64) {
+	for i := range px {
+		px[i] += vx[i]
+	}
+}
+
+func step(px, vx *[4]int64) {
+	applyGravity(px, vx)
+	applyVelocity(px, vx)
+}
+
+func simulate(s state, n int) state {
+	for i := 0; i < n; i++ {
+		step(&s.px, &s.vx)
+		step(&s.py, &s.vy)
+		step(&s.pz, &s.vz)
+	}
+	return s
+}
+
+func energy(s state) int64 {
+	e := int64(0)
+	for i := 0; i < len(s.px); i++ {
+		pe := ints.Abs(s.px[i])
+		pe += ints.Abs(s.py[i])
+		pe += ints.Abs(s.pz[i])
+		ke := ints.Abs(s.vx[i])
+		ke += ints.Abs(s.vy[i])
+		ke += ints.Abs(s.vz[i])
+		e += pe * ke
+	}
+	return e
+}
+
+func findLoopCoord(px, vx [4]int64, ch chan<- int64) {
+	pi, vi := px, vx
+	for i := int64(1); ; i++ {
+		step(&px, &vx)
+		if px == pi && vx == vi {
+			ch <- i
+			return
+		}
+	}
+}
+
+func findLoop(s state) (int64, error) {
+	ch := make(chan int64)
+	defer close(ch)
+	go findLoopCoord(s.px, s.vx, ch)
+	go findLoopCoord(s.py, s.vy, ch)
+	go findLoopCoord(s.pz, s.vz, ch)
+	xs := []int64{<-ch, <-ch, <-ch}
+	l, ok := ints.Lcm(xs...)
+	if !ok {
+		return 0, fmt.Errorf("loop lengths %v overflow their lcm", xs)
+	}
+	return l, nil
+}
+
+// Part1 returns the total energy in the system after 1000 steps.
+func Part1(r io.Reader) (string, error) {
+	s, err := readState(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(energy(simulate(s, 1000)), 10), nil
+}
+
+// Part2 returns the number of steps before the moons first return to a
+// previous state.
+func Part2(r io.Reader) (string, error) {
+	s, err := readState(r)
+	if err != nil {
+		return "", err
+	}
+	n, err := findLoop(s)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(n, 10), nil
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day13"
+)
+
+func main() {
+	aoc.Main(day13.Part1, day13.Part2)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/dhconnelly/advent-of-code-2019/breakout"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/gdamore/tcell"
+)
+
+var record = flag.String("record", "", "write the game's I/O session to this file")
+
+func main() {
+	flag.Parse()
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = screen.Init(); err != nil {
+		log.Fatal(err)
+	}
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(data) == 0 {
+		log.Fatal("empty program")
+	}
+	data[0] = 2 // play for free
+	var session *intcode.Session
+	if *record != "" {
+		session = &intcode.Session{}
+	}
+	state, err := breakout.Record(data, screen, 330000000, breakout.NEUTRAL, session)
+	if err != nil {
+		log.Fatal(err)
+	}
+	screen.Fini()
+	fmt.Println(state.Score)
+	if session != nil {
+		if err := session.WriteFile(*record); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -1,9 +1,10 @@
-package main
+// Package day13 plays the arcade cabinet's breakout game.
+package day13
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/breakout"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
@@ -22,22 +23,34 @@ func countTiles(
 	return n
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+// Part1 returns the number of block tiles on the screen when the game
+// exits.
+func Part1(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-
-	state, err := breakout.Play(data, nil, 1, breakout.NEUTRAL)
+	state, err := breakout.Autoplay(data)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	fmt.Println(countTiles(state, breakout.BLOCK))
+	return strconv.Itoa(countTiles(state, breakout.BLOCK)), nil
+}
 
+// Part2 plays the game for free until every block is broken and returns
+// the final score.
+func Part2(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("empty program")
+	}
 	data[0] = 2 // play for free
-	state, err = breakout.Play(data, nil, 1, breakout.LEFT)
+	state, err := breakout.Autoplay(data)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	fmt.Println(state.Score)
+	return strconv.Itoa(state.Score), nil
 }
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day14"
+)
+
+func main() {
+	aoc.Main(day14.Part1, day14.Part2)
+}
@@ -1,69 +1,73 @@
-package main
+// Package day14 works out how much ore the nanofactory needs to make
+// fuel.
+package day14
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"os"
-	"strings"
+	"strconv"
 
+	"github.com/dhconnelly/advent-of-code-2019/input"
 	"github.com/dhconnelly/advent-of-code-2019/ints"
 )
 
-type quant struct {
-	amt  int
-	chem string
+// Quant is an amount of a chemical.
+type Quant struct {
+	Amt  int
+	Chem string
 }
 
-type reaction struct {
-	out quant
-	ins []quant
+// Reaction makes Out from Ins.
+type Reaction struct {
+	Out Quant
+	Ins []Quant
 }
 
-func readQuant(s string) quant {
-	var q quant
-	_, err := fmt.Sscanf(s, "%d %s", &q.amt, &q.chem)
+// Reactions maps each chemical to the only reaction that makes it.
+type Reactions map[string]Reaction
+
+var quantParser = input.MustParser[Quant](`(\d+) (\w+)`)
+
+// ReadReactions reads one reaction per line, e.g. "7 A, 1 B => 1 C".
+func ReadReactions(r io.Reader) (Reactions, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatalf("failed to read quant %s: %s", s, err)
+		return nil, err
 	}
-	return q
-}
-
-func readReactions(r io.Reader) map[string]reaction {
-	scan := bufio.NewScanner(r)
-	reacts := make(map[string]reaction)
-	for scan.Scan() {
-		line := scan.Text()
-		parts := strings.Split(line, "=>")
-		out := readQuant(parts[1])
-		var ins []quant
-		for _, tok := range strings.Split(parts[0], ",") {
-			ins = append(ins, readQuant(tok))
+	reacts := make(Reactions)
+	for _, line := range lines {
+		lhs, rhs, err := line.Cut("=>")
+		if err != nil {
+			return nil, err
 		}
-		reacts[out.chem] = reaction{out, ins}
-	}
-	if err := scan.Err(); err != nil {
-		log.Fatal(err)
+		out, err := quantParser.Parse(rhs)
+		if err != nil {
+			return nil, err
+		}
+		if out.Amt == 0 {
+			return nil, rhs.Errorf(0, "reaction makes no %s", out.Chem)
+		}
+		var ins []Quant
+		for _, tok := range lhs.Split(",") {
+			in, err := quantParser.Parse(tok)
+			if err != nil {
+				return nil, err
+			}
+			ins = append(ins, in)
+		}
+		reacts[out.Chem] = Reaction{out, ins}
 	}
-	return reacts
+	return reacts, nil
 }
 
-func divceil(m, n int) int {
-	q := m / n
-	if m%n == 0 {
-		return q
-	}
-	return q + 1
+// OreNeeded returns the ORE needed to make amt of chem.
+func (reacts Reactions) OreNeeded(chem string, amt int) (int, error) {
+	return reacts.oreNeeded(chem, amt, map[string]int{})
 }
 
-func oreNeeded(
-	chem string, amt int,
-	reacts map[string]reaction,
-	waste map[string]int,
-) int {
+func (reacts Reactions) oreNeeded(chem string, amt int, waste map[string]int) (int, error) {
 	if chem == "ORE" {
-		return amt
+		return amt, nil
 	}
 
 	// reuse excess production before building more
@@ -73,49 +77,73 @@ func oreNeeded(
 		amt -= reclaimed
 	}
 	if amt == 0 {
-		return 0
+		return 0, nil
 	}
 
 	// build as much as necessary and store the excess
-	react := reacts[chem]
+	react, ok := reacts[chem]
+	if !ok {
+		return 0, fmt.Errorf("no reaction makes %s", chem)
+	}
 	k := 1
-	if amt > react.out.amt {
-		k = divceil(amt, react.out.amt)
+	if amt > react.Out.Amt {
+		k = ints.DivCeil(amt, react.Out.Amt)
 	}
-	waste[chem] += k*react.out.amt - amt
+	waste[chem] += k*react.Out.Amt - amt
 
 	// recursively find the ore needed for the ingredients
 	ore := 0
-	for _, in := range react.ins {
-		ore += oreNeeded(in.chem, k*in.amt, reacts, waste)
+	for _, in := range react.Ins {
+		n, err := reacts.oreNeeded(in.Chem, k*in.Amt, waste)
+		if err != nil {
+			return 0, err
+		}
+		ore += n
 	}
-	return ore
+	return ore, nil
 }
 
-func maxFuel(ore int, reacts map[string]reaction) int {
-	lower, upper := 0, ore
-	for target := upper / 2; lower != target; {
-		needed := oreNeeded("FUEL", target, reacts, map[string]int{})
-		if needed > ore {
-			upper = target
-			target = lower + (target-lower)/2
-		} else if needed < ore {
-			lower = target
-			target = target + (upper-target)/2
+// MaxFuel returns the most FUEL that can be made from the given ORE.
+func (reacts Reactions) MaxFuel(ore int) (int, error) {
+	// each FUEL takes at least one ORE, so the answer is in [lo, hi]
+	lo, hi := 0, ore
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		needed, err := reacts.OreNeeded("FUEL", mid)
+		if err != nil {
+			return 0, err
+		}
+		if needed <= ore {
+			lo = mid
 		} else {
-			return target
+			hi = mid - 1
 		}
 	}
-	return lower
+	return lo, nil
+}
+
+// Part1 returns the ore needed to make 1 FUEL.
+func Part1(r io.Reader) (string, error) {
+	reacts, err := ReadReactions(r)
+	if err != nil {
+		return "", err
+	}
+	ore, err := reacts.OreNeeded("FUEL", 1)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(ore), nil
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part2 returns the most FUEL that can be made from a trillion ore.
+func Part2(r io.Reader) (string, error) {
+	reacts, err := ReadReactions(r)
+	if err != nil {
+		return "", err
+	}
+	fuel, err := reacts.MaxFuel(1000000000000)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	defer f.Close()
-	reacts := readReactions(f)
-	fmt.Println(oreNeeded("FUEL", 1, reacts, map[string]int{}))
-	fmt.Println(maxFuel(1000000000000, reacts))
+	return strconv.Itoa(fuel), nil
 }
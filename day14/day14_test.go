@@ -0,0 +1,65 @@
+package day14
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func readTest(t *testing.T, path string) Reactions {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reacts, err := ReadReactions(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reacts
+}
+
+func TestOreNeeded(t *testing.T) {
+	for path, want := range map[string]int{
+		"test1.txt": 31,
+		"test2.txt": 165,
+		"test3.txt": 13312,
+		"test4.txt": 180697,
+		"test5.txt": 2210736,
+	} {
+		got, err := readTest(t, path).OreNeeded("FUEL", 1)
+		if err != nil || got != want {
+			t.Errorf("%s: got %d, %v; want %d", path, got, err, want)
+		}
+	}
+}
+
+func TestMaxFuel(t *testing.T) {
+	for path, want := range map[string]int{
+		"test3.txt": 82892753,
+		"test4.txt": 5586022,
+		"test5.txt": 460664,
+	} {
+		got, err := readTest(t, path).MaxFuel(1000000000000)
+		if err != nil || got != want {
+			t.Errorf("%s: got %d, %v; want %d", path, got, err, want)
+		}
+	}
+	reacts, err := ReadReactions(strings.NewReader("10 ORE => 10 A\n1 A => 1 FUEL\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reacts.MaxFuel(100); err != nil || got != 100 {
+		t.Errorf("MaxFuel(100) = %d, %v; want 100", got, err)
+	}
+}
+
+func TestUnknownChemical(t *testing.T) {
+	reacts, err := ReadReactions(strings.NewReader("10 ORE, 1 B => 1 FUEL\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reacts.OreNeeded("FUEL", 1); err == nil {
+		t.Error("no error for an ingredient nothing makes")
+	}
+}
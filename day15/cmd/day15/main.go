@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day15"
+)
+
+func main() {
+	aoc.MainViz(day15.Part1, day15.Part2, day15.Visualize)
+}
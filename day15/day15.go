@@ -1,12 +1,16 @@
-package main
+// Package day15 maps the ship's compartments with a repair droid to find
+// the oxygen system.
+package day15
 
 import (
 	"fmt"
+	"io"
+	"strconv"
+
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
 	"github.com/dhconnelly/advent-of-code-2019/ints"
-	"log"
-	"os"
+	"github.com/dhconnelly/advent-of-code-2019/search"
 )
 
 type status int
@@ -37,96 +41,92 @@ func opposite(dir direction) direction {
 	case EAST:
 		return WEST
 	}
-	log.Fatal("bad direction:", dir)
-	return 0
+	panic(fmt.Sprintf("bad direction: %d", dir))
 }
 
 var directions = map[direction]geom.Pt2{
-	NORTH: geom.Pt2{0, 1},
-	SOUTH: geom.Pt2{0, -1},
-	WEST:  geom.Pt2{-1, 0},
-	EAST:  geom.Pt2{1, 0},
+	NORTH: geom.Pt2{X: 0, Y: 1},
+	SOUTH: geom.Pt2{X: 0, Y: -1},
+	WEST:  geom.Pt2{X: -1, Y: 0},
+	EAST:  geom.Pt2{X: 1, Y: 0},
 }
 
 type droid struct {
-	in  chan<- int64
-	out <-chan int64
+	in      chan<- int64
+	out     <-chan int64
+	moved   func(m map[geom.Pt2]status, at geom.Pt2) // if not nil
+	stopped bool                                     // the program stopped
+}
+
+func (d *droid) report(m map[geom.Pt2]status, at geom.Pt2) {
+	if d.moved != nil {
+		d.moved(m, at)
+	}
 }
 
 func (d *droid) step(dir direction) status {
 	d.in <- int64(dir)
-	return status(<-d.out)
+	s, ok := <-d.out
+	if !ok {
+		d.stopped = true
+		return WALL
+	}
+	return status(s)
 }
 
 func (d *droid) visit(p geom.Pt2, m map[geom.Pt2]status) {
 	for dir, dp := range directions {
+		if d.stopped {
+			return
+		}
 		next := p.Add(dp)
 		if _, ok := m[next]; ok {
 			continue
 		}
 		s := d.step(dir)
 		if m[next] = s; s == WALL {
+			d.report(m, p)
 			continue
 		}
+		d.report(m, next)
 		d.visit(next, m)
 		d.step(opposite(dir))
+		d.report(m, p)
 	}
 }
 
-func explore(prog []int64) map[geom.Pt2]status {
-	in := make(chan int64)
-	out := intcode.RunProgram(prog, in)
-	d := droid{in, out}
+// Explores the whole area, calling moved, if it isn't nil, each time the
+// droid tries to move.
+func explore(prog []int64, moved func(m map[geom.Pt2]status, at geom.Pt2)) (map[geom.Pt2]status, error) {
+	// buffered so a program that writes before reading can't block us
+	in := make(chan int64, 1)
+	defer close(in)
+	out, wait := intcode.Start(prog, in)
+	d := droid{in: in, out: out, moved: moved}
 	m := map[geom.Pt2]status{geom.Zero2: OK}
 	d.visit(geom.Zero2, m)
-	return m
+	if d.stopped {
+		if err := wait(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("droid stopped before exploring the whole area")
+	}
+	return m, nil
 }
 
-func findOxygen(m map[geom.Pt2]status) geom.Pt2 {
+func findOxygen(m map[geom.Pt2]status) (geom.Pt2, error) {
 	for p, s := range m {
 		if s == OXGN {
-			return p
+			return p, nil
 		}
 	}
-	log.Fatal("oxygen not found")
-	return geom.Zero2
-}
-
-type node struct {
-	p geom.Pt2
-	n int
+	return geom.Zero2, fmt.Errorf("oxygen not found")
 }
 
 func shortestPaths(from geom.Pt2, m map[geom.Pt2]status) map[geom.Pt2]int {
-	// track which nodes we've visited and how far away they are
-	visited := make(map[geom.Pt2]bool)
-	dist := make(map[geom.Pt2]int)
-
-	// keep a queue of the next nodes to visit, in sorted order, with closer
-	// nodes always before further ones
-	q := []node{{from, 0}}
-	var nd node
-
-	// continue as long as the queue is empty
-	for len(q) > 0 {
-		// pop the head off the queue and record its distance
-		nd, q = q[0], q[1:]
-		dist[nd.p] = nd.n
-
-		// add each unvisited neighbor to the end of the visit queue, with
-		// distance one greater than the distance of the current node
-		for _, dp := range directions {
-			nbr := nd.p.Add(dp)
-			if visited[nbr] {
-				continue
-			}
-			visited[nbr] = true
-			if m[nbr] != WALL {
-				q = append(q, node{nbr, nd.n + 1})
-			}
-		}
-	}
-	return dist
+	return search.BFS(from, search.GridNeighbors(func(p geom.Pt2) bool {
+		return m[p] != WALL
+	})).Dist
 }
 
 func shortestPath(from, to geom.Pt2, m map[geom.Pt2]status) int {
@@ -141,13 +141,37 @@ func longestPath(from geom.Pt2, m map[geom.Pt2]status) int {
 	return max
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+func explored(r io.Reader) (map[geom.Pt2]status, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return explore(data, nil)
+}
+
+// Part1 returns the fewest movements the droid needs to reach the oxygen
+// system from its starting position.
+func Part1(r io.Reader) (string, error) {
+	m, err := explored(r)
+	if err != nil {
+		return "", err
+	}
+	oxygen, err := findOxygen(m)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(shortestPath(geom.Zero2, oxygen, m)), nil
+}
+
+// Part2 returns the minutes it takes for oxygen to fill the area.
+func Part2(r io.Reader) (string, error) {
+	m, err := explored(r)
+	if err != nil {
+		return "", err
+	}
+	oxygen, err := findOxygen(m)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	m := explore(data)
-	p := findOxygen(m)
-	fmt.Println(shortestPath(geom.Zero2, p, m))
-	fmt.Println(longestPath(p, m))
+	return strconv.Itoa(longestPath(oxygen, m)), nil
 }
@@ -0,0 +1,82 @@
+package day15
+
+import (
+	"image/color"
+	"io"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+const (
+	unknownCell = iota
+	wallCell
+	openCell
+	oxygenCell
+	droidCell
+)
+
+var palette = color.Palette{
+	unknownCell: color.Black,
+	wallCell:    color.RGBA{96, 96, 96, 255},
+	openCell:    color.White,
+	oxygenCell:  color.RGBA{64, 128, 255, 255},
+	droidCell:   color.RGBA{255, 64, 64, 255},
+}
+
+var statusCell = map[status]uint8{WALL: wallCell, OK: openCell, OXGN: oxygenCell}
+
+// Draws the area explored so far, with the droid at the given position
+// if there is one.
+func snapshot(m map[geom.Pt2]status, droid *geom.Pt2) *viz.Frame {
+	g := geom.NewSparse[status]()
+	for p, s := range m {
+		g.Set(p, s)
+	}
+	f := viz.Snapshot(g, geom.Cartesian, func(s status, ok bool) uint8 {
+		if !ok {
+			return unknownCell
+		}
+		return statusCell[s]
+	})
+	if droid != nil {
+		f.Set(geom.Pt2{X: droid.X, Y: -droid.Y}, droidCell)
+	}
+	return f
+}
+
+// Visualize records the droid exploring the area and then the oxygen
+// spreading through it, one frame per minute.
+func Visualize(r io.Reader) (*viz.Recorder, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	rec := viz.NewRecorder(palette)
+	rec.Scale, rec.FPS, rec.Every = 8, 30, 4
+	m, err := explore(data, func(m map[geom.Pt2]status, at geom.Pt2) {
+		rec.Add(snapshot(m, &at))
+	})
+	if err != nil {
+		return nil, err
+	}
+	oxygen, err := findOxygen(m)
+	if err != nil {
+		return nil, err
+	}
+	dist := shortestPaths(oxygen, m)
+	filled := make(map[geom.Pt2]status)
+	for p, s := range m {
+		filled[p] = s
+	}
+	for t := 0; t <= longestPath(oxygen, m); t++ {
+		for p, d := range dist {
+			if d == t {
+				filled[p] = OXGN
+			}
+		}
+		rec.Add(snapshot(filled, nil))
+	}
+	return rec, nil
+}
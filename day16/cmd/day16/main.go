@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day16"
+)
+
+func main() {
+	aoc.Main(day16.Part1, day16.Part2)
+}
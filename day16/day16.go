@@ -1,24 +1,15 @@
-package main
+// Package day16 cleans up a signal with the Flawed Frequency
+// Transmission algorithm.
+package day16
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
-	"strconv"
+	"io"
 
+	"github.com/dhconnelly/advent-of-code-2019/input"
 	"github.com/dhconnelly/advent-of-code-2019/ints"
 )
 
-func parseVec(b []byte) []int {
-	v := make([]int, len(b))
-	for i, a := range b {
-		v[i] = int(a - '0')
-	}
-	return v
-}
-
 func coef(row, col int) int {
 	switch (col / row) % 4 {
 	case 0:
@@ -32,14 +23,8 @@ func coef(row, col int) int {
 	}
 }
 
-func copied(data []int) []int {
-	data2 := make([]int, len(data))
-	copy(data2, data)
-	return data2
-}
-
 func fft(signal []int, phases int) []int {
-	signal = copied(signal)
+	signal = ints.Copied(signal)
 	scratch := make([]int, len(signal))
 	for ; phases > 0; phases-- {
 		for i := 0; i < len(signal); i++ {
@@ -62,12 +47,13 @@ func toSignal(signal []int) string {
 	return string(b)
 }
 
-func offset(b []byte) int {
-	i, err := strconv.Atoi(string(b[:7]))
-	if err != nil {
-		log.Fatal(err)
+// The message offset is given by the first seven digits.
+func offset(signal []int) int {
+	off := 0
+	for _, d := range signal[:7] {
+		off = 10*off + d
 	}
-	return i
+	return off
 }
 
 func sliceSignal(signal []int, from, to int) []int {
@@ -84,10 +70,6 @@ func extractMessage(signal []int, reps, phases, offset, digits int) []int {
 
 	// for offset >= len(signal)/2, coef(i) = 0 for i < offset/2 and 1 for
 	// i >= offset/2
-	if offset < len(signal)*reps/2 {
-		log.Fatal("offset too great:", offset)
-	}
-
 	for ; phases > 0; phases-- {
 		sum := 0
 		for i := n - 1; i >= 0; i-- {
@@ -99,18 +81,53 @@ func extractMessage(signal []int, reps, phases, offset, digits int) []int {
 	return msg[:digits]
 }
 
-func main() {
-	txt, err := ioutil.ReadFile(os.Args[1])
+const (
+	digits = 8
+	phases = 100
+	reps   = 10000
+)
+
+func readSignal(r io.Reader) ([]int, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	v := parseVec(bytes.TrimSpace(txt))
-	i := offset(txt)
+	var signal []int
+	for _, line := range lines {
+		for i := 0; i < len(line.Text); i++ {
+			c := line.Text[i]
+			if c < '0' || c > '9' {
+				return nil, line.Errorf(i, "bad digit %q", c)
+			}
+			signal = append(signal, int(c-'0'))
+		}
+	}
+	if len(signal) < digits {
+		return nil, fmt.Errorf("signal has %d digits, want at least %d", len(signal), digits)
+	}
+	return signal, nil
+}
 
-	const digits = 8
-	const phases = 100
-	const reps = 10000
+// Part1 returns the first eight digits of the signal after 100 phases of
+// FFT.
+func Part1(r io.Reader) (string, error) {
+	signal, err := readSignal(r)
+	if err != nil {
+		return "", err
+	}
+	return toSignal(fft(signal, phases)[:digits]), nil
+}
 
-	fmt.Println(toSignal(fft(v, phases)[:digits]))
-	fmt.Println(toSignal(extractMessage(v, reps, phases, i, digits)))
+// Part2 returns the eight-digit message embedded in the final output of
+// the real signal, which is the input repeated 10000 times.
+func Part2(r io.Reader) (string, error) {
+	signal, err := readSignal(r)
+	if err != nil {
+		return "", err
+	}
+	off := offset(signal)
+	if off < len(signal)*reps/2 || off+digits > len(signal)*reps {
+		return "", fmt.Errorf("can't extract the message at offset %d", off)
+	}
+	return toSignal(extractMessage(signal, reps, phases, off, digits)), nil
 }
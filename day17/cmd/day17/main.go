@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day17"
+)
+
+func main() {
+	aoc.Main(day17.Part1, day17.Part2)
+}
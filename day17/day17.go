@@ -1,76 +1,67 @@
-package main
+// Package day17 drives the vacuum robot along the scaffolding outside
+// the ship.
+package day17
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
 	"github.com/dhconnelly/advent-of-code-2019/ints"
 )
 
-type grid struct {
-	height, width int
-	g             map[geom.Pt2]rune
-}
-
-func (g grid) neighbors(p geom.Pt2) []geom.Pt2 {
+func neighbors(g *geom.Grid[rune], p geom.Pt2) []geom.Pt2 {
 	var nbrs []geom.Pt2
-	for _, nbr := range p.ManhattanNeighbors() {
-		if c, ok := g.g[nbr]; ok && c != '.' {
+	for _, nbr := range g.Neighbors(p) {
+		if g.At(nbr) != '.' {
 			nbrs = append(nbrs, nbr)
 		}
 	}
 	return nbrs
 }
 
-func readGridFrom(out <-chan int64) (grid, bool) {
-	g := grid{g: make(map[geom.Pt2]rune)}
-	var width int
+func readGridFrom(out <-chan int64) (*geom.Grid[rune], bool) {
+	var b strings.Builder
+	prev := int64(-1)
 	for ch := range out {
-		if ch == '\n' {
-			if width > 0 {
-				g.height++
-				g.width = width
-				width = 0
-				continue
-			} else {
-				return g, true
-			}
+		if ch == '\n' && prev == '\n' {
+			g, err := geom.ParseDense(strings.NewReader(b.String()), func(c rune) rune {
+				return c
+			})
+			return g, err == nil
 		}
-		g.g[geom.Pt2{width, g.height}] = rune(ch)
-		width++
+		b.WriteRune(rune(ch))
+		prev = ch
 	}
-	return grid{}, false
+	return nil, false
 }
 
-func readGrid(data []int64) grid {
-	data = ints.Copied64(data)
+func readGrid(data []int64) (*geom.Grid[rune], error) {
+	data = ints.Copied(data)
 	in := make(chan int64)
-	out := intcode.RunProgram(data, in)
+	out, wait := intcode.Start(data, in)
 	g, ok := readGridFrom(out)
 	if !ok {
-		log.Fatal("can't read grid")
+		for range out {
+		}
+		if err := wait(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("program didn't draw a grid")
 	}
-	return g
+	return g, nil
 }
 
-func readGraph(g grid) map[geom.Pt2][]geom.Pt2 {
+func readGraph(g *geom.Grid[rune]) map[geom.Pt2][]geom.Pt2 {
 	m := make(map[geom.Pt2][]geom.Pt2)
-	for i := 0; i < g.height; i++ {
-		for j := 0; j < g.width; j++ {
-			p := geom.Pt2{j, i}
-			if c := g.g[p]; c == '.' {
-				continue
-			}
-			var edges []geom.Pt2
-			for _, nbr := range g.neighbors(p) {
-				edges = append(edges, nbr)
-			}
-			m[p] = edges
+	g.Each(func(p geom.Pt2, c rune) {
+		if c != '.' {
+			m[p] = neighbors(g, p)
 		}
-	}
+	})
 	return m
 }
 
@@ -84,7 +75,7 @@ func intersections(m map[geom.Pt2][]geom.Pt2) []geom.Pt2 {
 	return ps
 }
 
-func alignmentSum(g grid) int {
+func alignmentSum(g *geom.Grid[rune]) int {
 	m := readGraph(g)
 	ps := intersections(m)
 	sum := 0
@@ -101,22 +92,27 @@ func writeLine(ch chan<- int64, line string) {
 	ch <- int64('\n')
 }
 
+// Reads a line, or whatever is left if the channel is closed first.
 func readLine(ch <-chan int64) string {
 	var s []rune
-	for {
-		c := <-ch
+	for c := range ch {
 		if c == '\n' {
-			return string(s)
+			break
 		}
 		s = append(s, rune(c))
 	}
+	return string(s)
 }
 
-func computeDust(data []int64, prog [4]string) int64 {
-	data = ints.Copied64(data)
-	data[0] = 2
+func computeDust(data []int64, prog [4]string) (int64, error) {
+	if len(data) == 0 {
+		return 0, fmt.Errorf("empty program")
+	}
+	data = ints.Copied(data)
+	data[0] = 2 // wake the robot up
 	in := make(chan int64)
-	out := intcode.RunProgram(data, in)
+	defer close(in)
+	out, wait := intcode.Start(data, in)
 	readGridFrom(out)
 	for _, line := range prog {
 		readLine(out)
@@ -128,7 +124,10 @@ func computeDust(data []int64, prog [4]string) int64 {
 	for c := range out {
 		answer = c
 	}
-	return answer
+	if err := wait(); err != nil {
+		return 0, err
+	}
+	return answer, nil
 }
 
 var prog = [4]string{
@@ -138,12 +137,30 @@ var prog = [4]string{
 	"L,12,L,6,R,12,R,8",
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+// Part1 returns the sum of the alignment parameters of the scaffold
+// intersections.
+func Part1(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	g, err := readGrid(data)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(alignmentSum(g)), nil
+}
+
+// Part2 returns the dust collected by the robot after visiting every
+// part of the scaffold.
+func Part2(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	dust, err := computeDust(data, prog)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	g := readGrid(data)
-	fmt.Println(alignmentSum(g))
-	fmt.Println(computeDust(data, prog))
+	return strconv.FormatInt(dust, 10), nil
 }
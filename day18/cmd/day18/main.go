@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day18"
+)
+
+func main() {
+	aoc.MainViz(day18.Part1, day18.Part2, day18.Visualize)
+}
@@ -1,13 +1,15 @@
-package main
+// Package day18 collects every key in the vault in the fewest steps.
+package day18
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/input"
+	"github.com/dhconnelly/advent-of-code-2019/ints"
+	"github.com/dhconnelly/advent-of-code-2019/search"
 )
 
 const (
@@ -32,17 +34,20 @@ func isKey(c rune) bool {
 	return 'a' <= c && c <= 'z'
 }
 
-type maze map[geom.Pt2]rune
+// Maze is a map of the vault: '#' is a wall, '.' is open, '@' is an
+// entrance, lowercase letters are keys and uppercase letters are the
+// doors they open.
+type Maze map[geom.Pt2]rune
 
-func (m maze) clone() maze {
-	n := maze(make(map[geom.Pt2]rune))
+func (m Maze) clone() Maze {
+	n := Maze(make(map[geom.Pt2]rune))
 	for k, v := range m {
 		n[k] = v
 	}
 	return n
 }
 
-func (m maze) maybeFind(c rune) (geom.Pt2, bool) {
+func (m Maze) maybeFind(c rune) (geom.Pt2, bool) {
 	for k, v := range m {
 		if c == v {
 			return k, true
@@ -51,16 +56,17 @@ func (m maze) maybeFind(c rune) (geom.Pt2, bool) {
 	return geom.Zero2, false
 }
 
-func (m maze) find(c rune) geom.Pt2 {
+// Find returns the position of c in the maze.
+func (m Maze) Find(c rune) (geom.Pt2, error) {
 	p, ok := m.maybeFind(c)
 	if !ok {
-		log.Fatalf("not found: %c", c)
-		return geom.Zero2
+		return geom.Zero2, fmt.Errorf("no %c in maze", c)
 	}
-	return p
+	return p, nil
 }
 
-func (m maze) keys() []rune {
+// Keys returns every key in the maze.
+func (m Maze) Keys() []rune {
 	var keys []rune
 	for _, v := range m {
 		if isKey(v) {
@@ -70,7 +76,7 @@ func (m maze) keys() []rune {
 	return keys
 }
 
-func (m maze) adjacent(p geom.Pt2) []geom.Pt2 {
+func (m Maze) adjacent(p geom.Pt2) []geom.Pt2 {
 	var adj []geom.Pt2
 	for _, q := range p.ManhattanNeighbors() {
 		if c, ok := m[q]; ok && c != wall {
@@ -86,54 +92,56 @@ type bfsNode struct {
 	d int
 }
 
-func neighbors(m maze, p geom.Pt2) map[rune]bfsNode {
+// Returns whether the search from p stops at q: at the first key or door
+// in each direction.
+func (m Maze) stops(p, q geom.Pt2) bool {
+	c := m[q]
+	return q != p && (isDoor(c) || isKey(c))
+}
+
+func reachable(m Maze, p geom.Pt2) *search.Tree[geom.Pt2] {
+	return search.BFS(p, func(q geom.Pt2) []geom.Pt2 {
+		if m.stops(p, q) {
+			return nil
+		}
+		return m.adjacent(q)
+	})
+}
+
+func neighbors(m Maze, p geom.Pt2) map[rune]bfsNode {
 	nbrs := make(map[rune]bfsNode)
-	var first bfsNode
-	q := []bfsNode{{p, m[p], 0}}
-	v := map[geom.Pt2]bool{p: true}
-	for len(q) > 0 {
-		first, q = q[0], q[1:]
-		for _, adj := range m.adjacent(first.p) {
-			if v[adj] {
-				continue
-			}
-			v[adj] = true
-			c := m[adj]
-			nd := bfsNode{adj, c, first.d + 1}
-			if isDoor(c) || isKey(c) {
-				nbrs[c] = nd
-			} else {
-				q = append(q, nd)
-			}
+	for q, d := range reachable(m, p).Dist {
+		if m.stops(p, q) {
+			nbrs[m[q]] = bfsNode{q, m[q], d}
 		}
 	}
 	return nbrs
 }
 
-func readMaze(r io.Reader) maze {
-	scan := bufio.NewScanner(r)
-	m := maze(make(map[geom.Pt2]rune))
-	y := 0
-	for scan.Scan() {
-		x := 0
-		for _, c := range scan.Text() {
-			m[geom.Pt2{x, y}] = c
-			x++
-		}
-		y++
-	}
-	if err := scan.Err(); err != nil {
-		log.Fatal(err)
+// Returns a copy of the maze with the key at nd picked up and its door
+// opened.
+func (m Maze) take(nd bfsNode) Maze {
+	m2 := m.clone()
+	m2[nd.p] = open
+	if door, ok := m.maybeFind(doorFor(nd.c)); ok {
+		m2[door] = open
 	}
-	return m
+	return m2
 }
 
-func copied(x []rune) []rune {
-	y := make([]rune, len(x))
-	for i, v := range x {
-		y[i] = v
+// ReadMaze reads a maze one row per line.
+func ReadMaze(r io.Reader) (Maze, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	m := Maze(make(map[geom.Pt2]rune))
+	for y, line := range lines {
+		for x, c := range []rune(line.Text) {
+			m[geom.Pt2{X: x, Y: y}] = c
+		}
 	}
-	return y
+	return m, nil
 }
 
 func copiedPts(ps []geom.Pt2) []geom.Pt2 {
@@ -143,7 +151,7 @@ func copiedPts(ps []geom.Pt2) []geom.Pt2 {
 }
 
 func remove(rs []rune, i int) []rune {
-	return append(copied(rs[:i]), rs[i+1:]...)
+	return append(ints.Copied(rs[:i]), rs[i+1:]...)
 }
 
 func replace(ps []geom.Pt2, i int, p geom.Pt2) []geom.Pt2 {
@@ -152,13 +160,22 @@ func replace(ps []geom.Pt2, i int, p geom.Pt2) []geom.Pt2 {
 	return ps
 }
 
-var memo = make(map[string]int)
-
 func key(pos []geom.Pt2, need []rune) string {
 	return fmt.Sprintf("%v-%s", pos, string(need))
 }
 
-func multiShortestPath(pos []geom.Pt2, m maze, need []rune) int {
+// MultiShortestPath returns the fewest steps needed for robots starting
+// at pos to collect the needed keys between them, moving one at a time.
+// A key opens its door for every robot once it's collected.
+func MultiShortestPath(m Maze, pos []geom.Pt2, need []rune) (int, error) {
+	d := multiShortestPath(pos, m, need, make(map[string]int))
+	if d < 0 {
+		return 0, fmt.Errorf("can't collect keys %s", string(need))
+	}
+	return d, nil
+}
+
+func multiShortestPath(pos []geom.Pt2, m Maze, need []rune, memo map[string]int) int {
 	mk := key(pos, need)
 	if d, ok := memo[mk]; ok {
 		return d
@@ -166,7 +183,7 @@ func multiShortestPath(pos []geom.Pt2, m maze, need []rune) int {
 	if len(need) == 0 {
 		return 0
 	}
-	shortest := 0
+	shortest := -1
 	for j, from := range pos {
 		nbrs := neighbors(m, from)
 		for i, key := range need {
@@ -174,25 +191,22 @@ func multiShortestPath(pos []geom.Pt2, m maze, need []rune) int {
 			if !ok {
 				continue
 			}
-			m2 := m.clone()
-			m2[nd.p] = open
-			if door, ok := m.maybeFind(doorFor(nd.c)); ok {
-				m2[door] = open
+			sub := multiShortestPath(replace(pos, j, nd.p), m.take(nd), remove(need, i), memo)
+			if sub < 0 {
+				continue
 			}
-			subSteps := multiShortestPath(replace(pos, j, nd.p), m2, remove(need, i)) + nd.d
-			if subSteps >= 0 && (shortest == 0 || subSteps < shortest) {
-				shortest = subSteps
+			if d := sub + nd.d; shortest < 0 || d < shortest {
+				shortest = d
 			}
 		}
 	}
-	if shortest > 0 {
-		memo[mk] = shortest
-		return shortest
-	}
-	return -1
+	memo[mk] = shortest
+	return shortest
 }
 
-func splitMaze(m maze, atPoint geom.Pt2) []geom.Pt2 {
+// Split walls off the entrance at atPoint and the cells around it,
+// leaving four entrances in the diagonal cells, and returns them.
+func (m Maze) Split(atPoint geom.Pt2) []geom.Pt2 {
 	m[atPoint] = wall
 	m[atPoint.Go(geom.Left)] = wall
 	m[atPoint.Go(geom.Right)] = wall
@@ -209,18 +223,37 @@ func splitMaze(m maze, atPoint geom.Pt2) []geom.Pt2 {
 	return []geom.Pt2{p1, p2, p3, p4}
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part1 returns the fewest steps needed to collect every key.
+func Part1(r io.Reader) (string, error) {
+	m, err := ReadMaze(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	defer f.Close()
-	m := readMaze(f)
-
-	steps1 := multiShortestPath([]geom.Pt2{m.find(entr)}, m, m.keys())
-	fmt.Println(steps1)
+	p, err := m.Find(entr)
+	if err != nil {
+		return "", err
+	}
+	d, err := MultiShortestPath(m, []geom.Pt2{p}, m.Keys())
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(d), nil
+}
 
-	pos := splitMaze(m, m.find(entr))
-	steps2 := multiShortestPath(pos, m, m.keys())
-	fmt.Println(steps2)
+// Part2 returns the fewest steps needed to collect every key after the
+// vault is split into four sections, each with its own robot.
+func Part2(r io.Reader) (string, error) {
+	m, err := ReadMaze(r)
+	if err != nil {
+		return "", err
+	}
+	p, err := m.Find(entr)
+	if err != nil {
+		return "", err
+	}
+	d, err := MultiShortestPath(m, m.Split(p), m.Keys())
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(d), nil
 }
@@ -0,0 +1,111 @@
+package day18
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+func readTest(t *testing.T, path string) Maze {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := ReadMaze(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMultiShortestPath(t *testing.T) {
+	for _, tc := range []struct {
+		path   string
+		robots string
+		want   int
+	}{
+		{"test1.txt", "@", 132},
+		{"test2.txt", "@", 136},
+		{"test3.txt", "@", 81},
+		{"test4.txt", "1234", 32},
+		{"test5.txt", "1234", 72},
+	} {
+		m := readTest(t, tc.path)
+		var pos []geom.Pt2
+		for _, c := range tc.robots {
+			p, err := m.Find(c)
+			if err != nil {
+				t.Fatalf("%s: %s", tc.path, err)
+			}
+			pos = append(pos, p)
+		}
+		got, err := MultiShortestPath(m, pos, m.Keys())
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %d, %v; want %d", tc.path, got, err, tc.want)
+		}
+	}
+}
+
+func TestUnreachableKey(t *testing.T) {
+	for _, maze := range []string{
+		"#####\n#@#a#\n#####\n",       // nothing reachable
+		"#######\n#@..a#b\n#######\n", // only a reachable
+	} {
+		m, err := ReadMaze(strings.NewReader(maze))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := m.Find('@')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d, err := MultiShortestPath(m, []geom.Pt2{p}, m.Keys()); err == nil {
+			t.Errorf("%q: walked through a wall in %d steps", maze, d)
+		}
+		if _, err := replay(m, []geom.Pt2{p}, m.Keys(), func(Maze, []geom.Pt2) {}); err == nil {
+			t.Errorf("%q: replayed through a wall", maze)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	for _, tc := range []struct {
+		path   string
+		robots string
+		want   int
+	}{
+		{"test3.txt", "@", 81},
+		{"test4.txt", "1234", 32},
+	} {
+		m := readTest(t, tc.path)
+		var pos []geom.Pt2
+		for _, c := range tc.robots {
+			p, err := m.Find(c)
+			if err != nil {
+				t.Fatalf("%s: %s", tc.path, err)
+			}
+			pos = append(pos, p)
+		}
+		var last Maze
+		prev := pos
+		got, err := replay(m, pos, m.Keys(), func(m Maze, pos []geom.Pt2) {
+			moves := 0
+			for i := range pos {
+				moves += pos[i].ManhattanDist(prev[i])
+			}
+			if last != nil && moves != 1 {
+				t.Errorf("%s: robots moved from %v to %v", tc.path, prev, pos)
+			}
+			last, prev = m, pos
+		})
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %d, %v; want %d", tc.path, got, err, tc.want)
+		}
+		if keys := last.Keys(); len(keys) > 0 {
+			t.Errorf("%s: keys %s left", tc.path, string(keys))
+		}
+	}
+}
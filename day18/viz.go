@@ -0,0 +1,135 @@
+package day18
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+const (
+	emptyCell = iota
+	wallCell
+	openCell
+	trailCell
+	doorCell
+	keyCell
+	robotCell
+)
+
+var palette = color.Palette{
+	emptyCell: color.Black,
+	wallCell:  color.RGBA{64, 64, 72, 255},
+	openCell:  color.RGBA{200, 200, 200, 255},
+	trailCell: color.RGBA{128, 192, 255, 255},
+	doorCell:  color.RGBA{160, 96, 32, 255},
+	keyCell:   color.RGBA{255, 200, 0, 255},
+	robotCell: color.RGBA{255, 64, 64, 255},
+}
+
+// Replays the fewest steps for the robots at pos to collect the needed
+// keys, calling moved with the maze and the robots' positions before the
+// first step and after every one, and returns the number of steps.
+func replay(m Maze, pos []geom.Pt2, need []rune, moved func(m Maze, pos []geom.Pt2)) (int, error) {
+	memo := make(map[string]int)
+	left := multiShortestPath(pos, m, need, memo)
+	if left < 0 {
+		return 0, fmt.Errorf("can't collect keys %s", string(need))
+	}
+	moved(m, pos)
+	steps := 0
+	for len(need) > 0 {
+		j, i, nd, ok := nextKey(m, pos, need, left, memo)
+		if !ok {
+			return 0, fmt.Errorf("lost the way with keys %s left", string(need))
+		}
+		path := reachable(m, pos[j]).Path(nd.p)
+		for _, p := range path[1:] {
+			pos = replace(pos, j, p)
+			steps++
+			if p == nd.p {
+				m = m.take(nd)
+			}
+			moved(m, pos)
+		}
+		need = remove(need, i)
+		left -= nd.d
+	}
+	return steps, nil
+}
+
+// Finds a robot and key to collect next that leave the rest of the keys
+// collectable in the given number of steps.
+func nextKey(m Maze, pos []geom.Pt2, need []rune, left int, memo map[string]int) (int, int, bfsNode, bool) {
+	for j, from := range pos {
+		nbrs := neighbors(m, from)
+		for i, key := range need {
+			nd, ok := nbrs[key]
+			if !ok {
+				continue
+			}
+			rest := multiShortestPath(replace(pos, j, nd.p), m.take(nd), remove(need, i), memo)
+			if rest >= 0 && rest+nd.d == left {
+				return j, i, nd, true
+			}
+		}
+	}
+	return 0, 0, bfsNode{}, false
+}
+
+func snapshot(m Maze, trail map[geom.Pt2]bool, pos []geom.Pt2) *viz.Frame {
+	g := geom.NewSparse[rune]()
+	for p, c := range m {
+		g.Set(p, c)
+	}
+	f := viz.Snapshot(g, geom.Screen, func(c rune, ok bool) uint8 {
+		switch {
+		case !ok:
+			return emptyCell
+		case c == wall:
+			return wallCell
+		case isDoor(c):
+			return doorCell
+		case isKey(c):
+			return keyCell
+		}
+		return openCell
+	})
+	for p := range trail {
+		if m[p] == open {
+			f.Set(p, trailCell)
+		}
+	}
+	for _, p := range pos {
+		f.Set(p, robotCell)
+	}
+	return f
+}
+
+// Visualize records the robot collecting every key in the fewest steps,
+// one frame per step, leaving a trail where it has been.
+func Visualize(r io.Reader) (*viz.Recorder, error) {
+	m, err := ReadMaze(r)
+	if err != nil {
+		return nil, err
+	}
+	p, err := m.Find(entr)
+	if err != nil {
+		return nil, err
+	}
+	rec := viz.NewRecorder(palette)
+	rec.Scale, rec.FPS, rec.Every = 4, 30, 8
+	trail := make(map[geom.Pt2]bool)
+	_, err = replay(m, []geom.Pt2{p}, m.Keys(), func(m Maze, pos []geom.Pt2) {
+		for _, p := range pos {
+			trail[p] = true
+		}
+		rec.Add(snapshot(m, trail, pos))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day19"
+)
+
+func main() {
+	aoc.Main(day19.Part1, day19.Part2)
+}
@@ -1,9 +1,10 @@
-package main
+// Package day19 surveys the tractor beam with a drone.
+package day19
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
@@ -21,14 +22,22 @@ type drone struct {
 	prog []int64
 }
 
-func (d drone) test(x, y int, debug bool) state {
-	prog := ints.Copied64(d.prog)
-	in := make(chan int64)
+func (d drone) test(x, y int) (state, error) {
+	prog := ints.Copied(d.prog)
+	// buffered so a program that writes before reading can't block us
+	in := make(chan int64, 2)
 	defer close(in)
-	out := intcode.Run(prog, in, debug)
+	out, wait := intcode.Start(prog, in)
 	in <- int64(x)
 	in <- int64(y)
-	return state(<-out)
+	s, ok := <-out
+	if !ok {
+		if err := wait(); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("drone halted without a reading at (%d, %d)", x, y)
+	}
+	return state(s), nil
 }
 
 type beamReadings struct {
@@ -36,21 +45,25 @@ type beamReadings struct {
 	m                   map[geom.Pt2]state
 }
 
-func mapBeamReadings(prog []int64, x, y, width, height int) beamReadings {
+func mapBeamReadings(prog []int64, x, y, width, height int) (beamReadings, error) {
 	d := drone{prog}
 	m := beamReadings{x, y, width, height, make(map[geom.Pt2]state)}
 	for j := x; j < x+width; j++ {
 		for i := y; i < y+height; i++ {
-			m.m[geom.Pt2{j, i}] = d.test(j, i, false)
+			s, err := d.test(j, i)
+			if err != nil {
+				return m, err
+			}
+			m.m[geom.Pt2{X: j, Y: i}] = s
 		}
 	}
-	return m
+	return m, nil
 }
 
 func printBeamReadings(m beamReadings) {
 	for x := m.x; x < m.x+m.width; x++ {
 		for y := m.y; y < m.y+m.height; y++ {
-			switch m.m[geom.Pt2{x, y}] {
+			switch m.m[geom.Pt2{X: x, Y: y}] {
 			case pulled:
 				fmt.Print("#")
 			case stationary:
@@ -94,16 +107,25 @@ func findRange(fromX, fromY, toX, toY, width, height int) (int, int) {
 	return 0, 0
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+// Part1 returns the number of points affected by the beam in the 50x50
+// area closest to the emitter.
+func Part1(r io.Reader) (string, error) {
+	data, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	m := mapBeamReadings(data, 0, 0, 50, 50)
-	fmt.Println(countBeamReadings(m))
+	m, err := mapBeamReadings(data, 0, 0, 50, 50)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(countBeamReadings(m)), nil
+}
 
+// Part2 returns 10000*X + Y for the point closest to the emitter at
+// which a 100x100 ship fits entirely within the beam.
+func Part2(r io.Reader) (string, error) {
 	// range chosen by dumping the formula in fastTest
 	// into desmos.com/calculator
 	x, y := findRange(1500, 1600, 2000, 2200, 100, 100)
-	fmt.Println(x*10000 + y)
+	return strconv.Itoa(x*10000 + y), nil
 }
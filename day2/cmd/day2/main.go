@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day2"
+)
+
+func main() {
+	aoc.Main(day2.Part1, day2.Part2)
+}
@@ -1,69 +1,98 @@
-package main
+// Package day2 runs the original intcode computer, with only add and
+// multiply instructions.
+package day2
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
+	"io"
 	"strconv"
-	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
 )
 
-func readData(path string) []int {
-	txt, err := ioutil.ReadFile(path)
+func readData(r io.Reader) ([]int, error) {
+	prog, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	// drop the comma
-	toks := strings.Split(string(txt[:len(txt)-1]), ",")
-	data := make([]int, len(toks))
-	for i, tok := range toks {
-		val, err := strconv.Atoi(tok)
-		if err != nil {
-			log.Fatal(err)
-		}
-		data[i] = val
+	data := make([]int, len(prog))
+	for i, v := range prog {
+		data[i] = int(v)
 	}
-	return data
+	return data, nil
 }
 
-func execute(data []int) {
+func execute(data []int) error {
 	for i := 0; i < len(data); i += 4 {
 		switch data[i] {
-		case 1:
-			data[data[i+3]] = data[data[i+1]] + data[data[i+2]]
-		case 2:
-			data[data[i+3]] = data[data[i+1]] * data[data[i+2]]
+		case 1, 2:
+			if i+3 >= len(data) {
+				return fmt.Errorf("truncated instruction at position %d", i)
+			}
+			l, r, dst := data[i+1], data[i+2], data[i+3]
+			for _, addr := range []int{l, r, dst} {
+				if addr < 0 || addr >= len(data) {
+					return fmt.Errorf("address out of range at position %d: %d", i, addr)
+				}
+			}
+			if data[i] == 1 {
+				data[dst] = data[l] + data[r]
+			} else {
+				data[dst] = data[l] * data[r]
+			}
 		case 99:
-			return
+			return nil
 		default:
-			log.Fatalf("undefined opcode at position %d: %d", i, data[i])
+			return fmt.Errorf("undefined opcode at position %d: %d", i, data[i])
 		}
 	}
+	return nil
 }
 
-func executeWith(data []int, noun, verb int) int {
+func executeWith(data []int, noun, verb int) (int, error) {
+	if len(data) < 3 {
+		return 0, fmt.Errorf("program too short for a noun and verb: %v", data)
+	}
 	local := make([]int, len(data))
 	copy(local, data)
 	local[1], local[2] = noun, verb
-	execute(local)
-	return local[0]
+	if err := execute(local); err != nil {
+		return 0, err
+	}
+	return local[0], nil
 }
 
-func main() {
-	data := readData(os.Args[1])
-
-	// part 1
-	fmt.Println(executeWith(data, 12, 2))
+// Part1 returns the value left at position 0 after running the program
+// with noun 12 and verb 2.
+func Part1(r io.Reader) (string, error) {
+	data, err := readData(r)
+	if err != nil {
+		return "", err
+	}
+	result, err := executeWith(data, 12, 2)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(result), nil
+}
 
-	// part 2
+// Part2 finds the noun and verb for which the program outputs 19690720
+// and returns 100*noun + verb.
+func Part2(r io.Reader) (string, error) {
+	data, err := readData(r)
+	if err != nil {
+		return "", err
+	}
 	for noun := 0; noun <= 99; noun++ {
 		for verb := 0; verb <= 99; verb++ {
-			result := executeWith(data, noun, verb)
+			result, err := executeWith(data, noun, verb)
+			if err != nil {
+				return "", err
+			}
 			if result == 19690720 {
-				fmt.Println(100*noun + verb)
-				return
+				return strconv.Itoa(100*noun + verb), nil
 			}
 		}
 	}
+	return "", fmt.Errorf("no noun and verb produce 19690720")
 }
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day20"
+)
+
+func main() {
+	aoc.Main(day20.Part1, day20.Part2)
+}
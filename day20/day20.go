@@ -1,13 +1,15 @@
-package main
+// Package day20 finds the shortest path through a donut maze whose
+// portals may lead to recursive copies of the maze.
+package day20
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/input"
+	"github.com/dhconnelly/advent-of-code-2019/search"
 )
 
 const (
@@ -22,68 +24,45 @@ type grid struct {
 	g      map[geom.Pt2]rune
 }
 
-func readGrid(r io.Reader) grid {
-	scan := bufio.NewScanner(r)
+func readGrid(r io.Reader) (grid, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return grid{}, err
+	}
 	g := grid{g: make(map[geom.Pt2]rune)}
-	for scan.Scan() {
+	for _, line := range lines {
 		g.width = 0
-		line := scan.Text()
-		for _, c := range line {
-			g.g[geom.Pt2{g.width, g.height}] = c
+		for _, c := range line.Text {
+			g.g[geom.Pt2{X: g.width, Y: g.height}] = c
 			g.width++
 		}
 		g.height++
 	}
-	if err := scan.Err(); err != nil {
-		log.Fatal(err)
-	}
-	return g
+	return g, nil
 }
 
-func mazeBounds(g grid) (outer, inner geom.Rect) {
-outerLo:
-	for y := 0; y < g.height; y++ {
-		for x := 0; x < g.width; x++ {
-			p := geom.Pt2{x, y}
-			if g.g[p] == wall {
-				outer.Lo = p
-				break outerLo
-			}
-		}
-	}
-outerHi:
-	for y := g.height - 1; y >= 0; y-- {
-		for x := g.width - 1; x >= 0; x-- {
-			p := geom.Pt2{x, y}
-			if g.g[p] == wall {
-				outer.Hi = p
-				break outerHi
-			}
-		}
-	}
-innerLo:
-	for y := outer.Lo.Y; y <= outer.Hi.Y; y++ {
-		for x := outer.Lo.X; x <= outer.Hi.X; x++ {
-			p := geom.Pt2{x, y}
-			if g.g[p] == empty {
-				inner.Lo = p
-				break innerLo
-			}
-		}
-	}
-innerHi:
-	for y := outer.Hi.Y; y >= outer.Lo.Y; y-- {
-		for x := outer.Hi.X; x >= outer.Lo.X; x-- {
-			p := geom.Pt2{x, y}
-			if g.g[p] == empty {
-				inner.Hi = p
-				break innerHi
+// Returns the first and last points in ps, which are in row-major
+// order, at which g has the cell c.
+func firstLast(g grid, ps []geom.Pt2, c rune) (first, last geom.Pt2) {
+	found := false
+	for _, p := range ps {
+		if g.g[p] == c {
+			if !found {
+				first, found = p, true
 			}
+			last = p
 		}
 	}
 	return
 }
 
+func mazeBounds(g grid) (outer, inner geom.Rect) {
+	all := geom.Rect{Lo: geom.Zero2, Hi: geom.Pt2{X: g.width - 1, Y: g.height - 1}}
+	outer.Lo, outer.Hi = firstLast(g, all.Points(), wall)
+	inner.Lo, inner.Hi = firstLast(g, outer.Points(), empty)
+	return
+}
+
 type label [2]rune
 
 func lbl(s string) label {
@@ -111,11 +90,11 @@ func addLabel(
 	adjs map[label][]geom.Pt2,
 	lbls map[geom.Pt2]label,
 ) {
-	p := from.Go(dir)
+	p := geom.Screen.Go(from, dir)
 	if !isLabel(g, p) {
 		return
 	}
-	a, b := g.g[p], g.g[p.Go(dir)]
+	a, b := g.g[p], g.g[geom.Screen.Go(p, dir)]
 	lbl := label([2]rune{a, b})
 	if reversed {
 		lbl = flip(lbl)
@@ -131,16 +110,16 @@ func findOuterLabels(
 	lbls map[geom.Pt2]label,
 ) {
 	for x := r.Lo.X; x <= r.Hi.X; x++ {
-		addLabel(g, geom.Pt2{x, r.Lo.Y}, geom.Down, true, adjs, lbls)
+		addLabel(g, geom.Pt2{X: x, Y: r.Lo.Y}, geom.Up, true, adjs, lbls)
 	}
 	for x := r.Lo.X; x <= r.Hi.X; x++ {
-		addLabel(g, geom.Pt2{x, r.Hi.Y}, geom.Up, false, adjs, lbls)
+		addLabel(g, geom.Pt2{X: x, Y: r.Hi.Y}, geom.Down, false, adjs, lbls)
 	}
 	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
-		addLabel(g, geom.Pt2{r.Lo.X, y}, geom.Left, true, adjs, lbls)
+		addLabel(g, geom.Pt2{X: r.Lo.X, Y: y}, geom.Left, true, adjs, lbls)
 	}
 	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
-		addLabel(g, geom.Pt2{r.Hi.X, y}, geom.Right, false, adjs, lbls)
+		addLabel(g, geom.Pt2{X: r.Hi.X, Y: y}, geom.Right, false, adjs, lbls)
 	}
 }
 
@@ -151,16 +130,16 @@ func findInnerLabels(
 	lbls map[geom.Pt2]label,
 ) {
 	for x := r.Lo.X; x <= r.Hi.X; x++ {
-		addLabel(g, geom.Pt2{x, r.Lo.Y - 1}, geom.Up, false, adjs, lbls)
+		addLabel(g, geom.Pt2{X: x, Y: r.Lo.Y - 1}, geom.Down, false, adjs, lbls)
 	}
 	for x := r.Lo.X; x <= r.Hi.X; x++ {
-		addLabel(g, geom.Pt2{x, r.Hi.Y + 1}, geom.Down, true, adjs, lbls)
+		addLabel(g, geom.Pt2{X: x, Y: r.Hi.Y + 1}, geom.Up, true, adjs, lbls)
 	}
 	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
-		addLabel(g, geom.Pt2{r.Lo.X - 1, y}, geom.Right, false, adjs, lbls)
+		addLabel(g, geom.Pt2{X: r.Lo.X - 1, Y: y}, geom.Right, false, adjs, lbls)
 	}
 	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
-		addLabel(g, geom.Pt2{r.Hi.X + 1, y}, geom.Left, true, adjs, lbls)
+		addLabel(g, geom.Pt2{X: r.Hi.X + 1, Y: y}, geom.Left, true, adjs, lbls)
 	}
 }
 
@@ -240,37 +219,23 @@ func (m maze) adjacent(from point) []point {
 	return nbrs
 }
 
-type bfsNode struct {
-	p point
-	d int
-}
-
 func shortestPath(
 	m maze,
 	from, to label,
 	eq func(p1, p2 point) bool,
-) int {
+) (int, error) {
+	if len(m.adjs[from]) == 0 || len(m.adjs[to]) == 0 {
+		return 0, fmt.Errorf("no portal %s or %s", from, to)
+	}
 	src := point{m.adjs[from][0], 0}
 	dst := point{m.adjs[to][0], 0}
-	q := []bfsNode{{src, 0}}
-	v := make(map[point]bool)
-	var first bfsNode
-	for len(q) > 0 {
-		first, q = q[0], q[1:]
-		if eq(first.p, dst) {
-			return first.d
-		}
-		v[first.p] = true
-		nbrs := m.adjacent(first.p)
-		for _, nbr := range nbrs {
-			if v[nbr] {
-				continue
-			}
-			q = append(q, bfsNode{nbr, first.d + 1})
-		}
+	t, found, ok := search.BFSTo(src, m.adjacent, func(p point) bool {
+		return eq(p, dst)
+	})
+	if !ok {
+		return 0, fmt.Errorf("path not found: %s -> %s", from, to)
 	}
-	log.Fatalf("path not found: %s -> %s", from, to)
-	return -1
+	return t.Dist[found], nil
 }
 
 func eq(p1, p2 point) bool {
@@ -281,13 +246,31 @@ func depthEq(p1, p2 point) bool {
 	return p1.p == p2.p && p1.depth == p2.depth
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part1 returns the fewest steps from AA to ZZ.
+func Part1(r io.Reader) (string, error) {
+	g, err := readGrid(r)
+	if err != nil {
+		return "", err
+	}
+	m := readMaze(g)
+	d, err := shortestPath(m, lbl("AA"), lbl("ZZ"), eq)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(d), nil
+}
+
+// Part2 returns the fewest steps from AA to ZZ when the inner portals
+// lead one level deeper into the maze and the outer ones one level back.
+func Part2(r io.Reader) (string, error) {
+	g, err := readGrid(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	g := readGrid(f)
 	m := readMaze(g)
-	fmt.Println(shortestPath(m, lbl("AA"), lbl("ZZ"), eq))
-	fmt.Println(shortestPath(m, lbl("AA"), lbl("ZZ"), depthEq))
+	d, err := shortestPath(m, lbl("AA"), lbl("ZZ"), depthEq)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(d), nil
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dhconnelly/advent-of-code-2019/day21"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: day21 input_file [script_file]...")
+		os.Exit(2)
+	}
+	data, err := intcode.ReadProgram(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(os.Args) == 2 {
+		if err := day21.Run(data, os.Stdin, os.Stdout, true); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for _, path := range os.Args[2:] {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = day21.Run(data, f, os.Stdout, false)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
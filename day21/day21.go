@@ -1,22 +1,27 @@
-package main
+// Package day21 programs the springdroid to survey the hull.
+package day21
 
 import (
 	"bufio"
+	_ "embed"
 	"fmt"
 	"io"
-	"log"
 	"math"
-	"os"
+	"strings"
 
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
 )
 
-func readLine(ch <-chan int64) string {
+// Reads a line, returning false if the channel is closed first.
+func readLine(ch <-chan int64) (string, bool) {
 	var data []byte
-	for c := byte(<-ch); c != '\n'; c = byte(<-ch) {
-		data = append(data, c)
+	for c := range ch {
+		if c == '\n' {
+			return string(data), true
+		}
+		data = append(data, byte(c))
 	}
-	return string(data)
+	return string(data), false
 }
 
 func writeLine(ch chan<- int64, s string) {
@@ -26,16 +31,23 @@ func writeLine(ch chan<- int64, s string) {
 	ch <- int64('\n')
 }
 
-type springdroid struct {
-	prog []int64
-}
-
-func (d springdroid) execute(r io.Reader, prompt bool) error {
+// Run loads the springscript read from r into the springdroid and runs
+// it, writing the hull damage it reports to w. If prompt is true, it
+// also writes the droid's prompt and any drawing of its last moments
+// before falling into space.
+func Run(prog []int64, r io.Reader, w io.Writer, prompt bool) error {
 	in := make(chan int64)
-	out := intcode.RunProgram(d.prog, in)
-	line := readLine(out)
+	defer close(in)
+	out, wait := intcode.Start(prog, in)
+	line, ok := readLine(out)
+	if !ok {
+		if err := wait(); err != nil {
+			return err
+		}
+		return fmt.Errorf("springdroid halted without a prompt")
+	}
 	if prompt {
-		fmt.Println(line)
+		fmt.Fprintln(w, line)
 	}
 	scan := bufio.NewScanner(r)
 	for scan.Scan() {
@@ -47,36 +59,42 @@ func (d springdroid) execute(r io.Reader, prompt bool) error {
 	}
 	for c, ok := <-out; ok; c, ok = <-out {
 		if c > math.MaxInt8 {
-			fmt.Printf("%d\n", c)
+			fmt.Fprintf(w, "%d\n", c)
 		} else if prompt {
-			fmt.Printf("%c", c)
+			fmt.Fprintf(w, "%c", c)
 		}
 	}
+	if err := wait(); err != nil {
+		return err
+	}
 	return scan.Err()
 }
 
-func openOrDie(path string) *os.File {
-	f, err := os.Open(path)
+var (
+	//go:embed part1.txt
+	walk string
+	//go:embed part2.txt
+	run string
+)
+
+func solve(r io.Reader, script string) (string, error) {
+	prog, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
+	}
+	var b strings.Builder
+	if err := Run(prog, strings.NewReader(script), &b, false); err != nil {
+		return "", err
 	}
-	return f
+	return strings.TrimSpace(b.String()), nil
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
-	if err != nil {
-		log.Fatal(err)
-	}
-	d := springdroid{data}
+// Part1 returns the hull damage reported by a springdroid that walks.
+func Part1(r io.Reader) (string, error) {
+	return solve(r, walk)
+}
 
-	if len(os.Args) > 2 {
-		for _, path := range os.Args[2:] {
-			f := openOrDie(path)
-			defer f.Close()
-			d.execute(f, false)
-		}
-	} else {
-		d.execute(os.Stdin, true)
-	}
+// Part2 returns the hull damage reported by a springdroid that runs.
+func Part2(r io.Reader) (string, error) {
+	return solve(r, run)
 }
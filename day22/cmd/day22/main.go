@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day22"
+)
+
+func main() {
+	aoc.Main(day22.Part1, day22.Part2)
+}
@@ -1,146 +1,166 @@
-package main
+// Package day22 shuffles a deck of space cards.
+package day22
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"math/big"
-	"os"
+	"strconv"
 	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/input"
+	"github.com/dhconnelly/advent-of-code-2019/ints"
 )
 
-var one = big.NewInt(1)
-var minusOne = big.NewInt(-1)
-var zero = big.NewInt(0)
+// Technique is a way of shuffling the deck.
+type Technique int
 
-type applier interface {
-	apply(n *big.Int, mod *big.Int)
-}
+const (
+	DealIntoNewStack Technique = iota
+	Cut
+	DealWithIncrement
+)
 
-type change struct {
-	scale *big.Int
-	shift *big.Int
+// Step is one use of a technique, with N the size of the cut or the
+// increment.
+type Step struct {
+	Tech Technique
+	N    int64
 }
 
-func (chg change) mod(mod int64) {
-	chg.scale.Mod(chg.scale, big.NewInt(mod))
-	chg.shift.Mod(chg.shift, big.NewInt(mod))
+// Shuffle moves the card at position n to position Scale*n + Shift,
+// modulo Mod, the size of the deck. Every sequence of steps is such a
+// shuffle, so they can be combined, undone and repeated cheaply.
+type Shuffle struct {
+	Scale, Shift, Mod int64
 }
 
-func (chg change) apply(n *big.Int, mod *big.Int) {
-	n.Mul(n, chg.scale)
-	n.Add(n, chg.shift)
-	n.Mod(n, mod)
+// Identity returns the shuffle that leaves a deck of the given size
+// alone.
+func Identity(mod int64) Shuffle {
+	return Shuffle{1, 0, mod}
 }
 
-func (chg change) invert(mod int64) invertedChange {
-	chg.mod(mod)
-	m := big.NewInt(mod)
-	scale := big.NewInt(0)
-	if chg.scale.Cmp(zero) != 0 {
-		scale = chg.scale.ModInverse(chg.scale, m)
+// NewShuffle returns the shuffle that performs the steps in order on a
+// deck of the given size.
+func NewShuffle(steps []Step, mod int64) Shuffle {
+	sh := Identity(mod)
+	for _, s := range steps {
+		sh = sh.Then(s)
 	}
-	shift := chg.shift.Mul(chg.shift, minusOne)
-	shift.Mul(shift, scale)
-	shift.Mod(shift, m)
-	return invertedChange{scale, shift}
+	return sh
 }
 
-type invertedChange struct {
-	scale *big.Int
-	shift *big.Int
+// Then returns the shuffle that performs sh and then the step.
+func (sh Shuffle) Then(s Step) Shuffle {
+	switch s.Tech {
+	case DealIntoNewStack:
+		sh.Scale = ints.SubMod(0, sh.Scale, sh.Mod)
+		sh.Shift = ints.SubMod(-1, sh.Shift, sh.Mod)
+	case Cut:
+		sh.Shift = ints.SubMod(sh.Shift, s.N, sh.Mod)
+	case DealWithIncrement:
+		sh.Scale = ints.MulMod(sh.Scale, s.N, sh.Mod)
+		sh.Shift = ints.MulMod(sh.Shift, s.N, sh.Mod)
+	}
+	return sh
 }
 
-func (ichg invertedChange) apply(n *big.Int, mod *big.Int) {
-	n.Mul(n, ichg.scale)
-	n.Add(n, ichg.shift)
-	n.Mod(n, mod)
+// Apply returns the position that the card at position n moves to.
+func (sh Shuffle) Apply(n int64) int64 {
+	return ints.AddMod(ints.MulMod(sh.Scale, n, sh.Mod), sh.Shift, sh.Mod)
 }
 
-func geoSum(a, r, n, mod *big.Int) {
-	var denom big.Int
-	denom.Set(one)
-	denom.Sub(&denom, r)
-	denom.ModInverse(&denom, mod)
-
-	r.Exp(r, n, mod)
-	r.Mul(r, minusOne)
-	r.Add(r, one)
-
-	r.Mul(r, &denom)
-	r.Mul(r, a)
-
-	a.Set(r)
-	a.Mod(a, mod)
+// Invert returns the shuffle that moves each card back to where it was.
+// The deck size must be prime.
+func (sh Shuffle) Invert() (Shuffle, error) {
+	scale, ok := ints.ModInverse(sh.Scale, sh.Mod)
+	if !ok {
+		return Shuffle{}, fmt.Errorf("can't invert shuffle modulo %d", sh.Mod)
+	}
+	shift := ints.MulMod(ints.SubMod(0, sh.Shift, sh.Mod), scale, sh.Mod)
+	return Shuffle{scale, shift, sh.Mod}, nil
 }
 
-func (ichg invertedChange) pow(n *big.Int, mod *big.Int) {
-	var scale big.Int
-	scale.Set(ichg.scale)
-	geoSum(ichg.shift, &scale, n, mod)
-	ichg.scale.Exp(ichg.scale, n, mod)
+// Pow returns the shuffle that performs sh n times. The deck size must
+// be prime.
+func (sh Shuffle) Pow(n int64) (Shuffle, error) {
+	scale := ints.ModPow(sh.Scale, n, sh.Mod)
+	// shift * (1 + scale + ... + scale^(n-1))
+	var sum int64
+	if sh.Scale == 1 {
+		sum = ints.Mod(n, sh.Mod)
+	} else {
+		denom, ok := ints.ModInverse(ints.SubMod(1, sh.Scale, sh.Mod), sh.Mod)
+		if !ok {
+			return Shuffle{}, fmt.Errorf("can't repeat shuffle modulo %d", sh.Mod)
+		}
+		sum = ints.MulMod(ints.SubMod(1, scale, sh.Mod), denom, sh.Mod)
+	}
+	return Shuffle{scale, ints.MulMod(sh.Shift, sum, sh.Mod), sh.Mod}, nil
 }
 
-func ReadTransformations(r io.Reader) change {
-	scan := bufio.NewScanner(r)
-	chg := change{big.NewInt(1), big.NewInt(0)}
-	var x big.Int
-	for scan.Scan() {
-		line := scan.Text()
+// ReadTransformations reads one step per line, e.g. "cut -2" or "deal
+// with increment 7".
+func ReadTransformations(r io.Reader) ([]Step, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	for _, line := range lines {
+		var s Step
+		var arg input.Field
 		switch {
-		case strings.Index(line, "deal with") == 0:
-			var d int64
-			fmt.Sscanf(line, "deal with increment %d", &d)
-			x.SetInt64(d)
-			chg.scale.Mul(chg.scale, &x)
-			chg.shift.Mul(chg.shift, &x)
-		case strings.Index(line, "cut") == 0:
-			var d int64
-			fmt.Sscanf(line, "cut %d", &d)
-			x.SetInt64(d)
-			chg.shift.Sub(chg.shift, &x)
-		case strings.Index(line, "deal into") == 0:
-			chg.scale.Mul(chg.scale, minusOne)
-			chg.shift.Mul(chg.shift, minusOne)
-			chg.shift.Sub(chg.shift, one)
+		case line.Text == "deal into new stack":
+			s.Tech = DealIntoNewStack
+		case strings.HasPrefix(line.Text, "cut "):
+			s.Tech = Cut
+			_, arg, _ = line.Cut("cut ")
+		case strings.HasPrefix(line.Text, "deal with increment "):
+			s.Tech = DealWithIncrement
+			_, arg, _ = line.Cut("increment ")
 		default:
-			log.Fatal("bad line:", line)
+			return nil, line.Errorf(0, "unknown technique %q", line.Text)
 		}
+		if s.Tech != DealIntoNewStack {
+			n, err := arg.Int()
+			if err != nil {
+				return nil, err
+			}
+			s.N = int64(n)
+		}
+		steps = append(steps, s)
 	}
-	if err := scan.Err(); err != nil {
-		log.Fatal(err)
-	}
-	return chg
-}
-
-func apply(n int64, mod int64, a applier, times int) int64 {
-	x := big.NewInt(n)
-	m := big.NewInt(mod)
-	for ; times > 0; times-- {
-		a.apply(x, m)
-		x.Mod(x, m)
-	}
-	return x.Int64()
+	return steps, nil
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part1 returns the position of card 2019 after shuffling a deck of
+// 10007 cards.
+func Part1(r io.Reader) (string, error) {
+	steps, err := ReadTransformations(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	defer f.Close()
-	chg := ReadTransformations(f)
-	fmt.Println(apply(2019, 10007, chg, 1))
+	return strconv.FormatInt(NewShuffle(steps, 10007).Apply(2019), 10), nil
+}
 
-	var mod int64 = 119315717514047
+// Part2 returns the card that ends up in position 2020 after shuffling a
+// deck of 119315717514047 cards 101741582076661 times.
+func Part2(r io.Reader) (string, error) {
+	const mod = 119315717514047
 	const n = 2020
 	const times = 101741582076661
-	i := chg.invert(mod)
-	m := big.NewInt(mod)
-	x := big.NewInt(n)
-	i.pow(big.NewInt(times), m)
-	i.apply(x, m)
-	x.Mod(x, m)
-	fmt.Println(x)
+	steps, err := ReadTransformations(r)
+	if err != nil {
+		return "", err
+	}
+	inv, err := NewShuffle(steps, mod).Invert()
+	if err != nil {
+		return "", err
+	}
+	rep, err := inv.Pow(times)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(rep.Apply(n), 10), nil
 }
@@ -0,0 +1,119 @@
+package day22
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	for _, tc := range []struct {
+		steps string
+		deck  []int64
+	}{
+		{"deal with increment 7\ndeal into new stack\ndeal into new stack",
+			[]int64{0, 3, 6, 9, 2, 5, 8, 1, 4, 7}},
+		{"cut 6\ndeal with increment 7\ndeal into new stack",
+			[]int64{3, 0, 7, 4, 1, 8, 5, 2, 9, 6}},
+		{"deal with increment 7\ndeal with increment 9\ncut -2",
+			[]int64{6, 3, 0, 7, 4, 1, 8, 5, 2, 9}},
+		{"deal into new stack\ncut -2\ndeal with increment 7\ncut 8\ncut -4\n" +
+			"deal with increment 7\ncut 3\ndeal with increment 9\n" +
+			"deal with increment 3\ncut -1",
+			[]int64{9, 2, 5, 8, 1, 4, 7, 0, 3, 6}},
+	} {
+		steps, err := ReadTransformations(strings.NewReader(tc.steps))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sh := NewShuffle(steps, 10)
+		deck := make([]int64, 10)
+		for card := int64(0); card < 10; card++ {
+			deck[sh.Apply(card)] = card
+		}
+		if !reflect.DeepEqual(deck, tc.deck) {
+			t.Errorf("%q: got %v, want %v", tc.steps, deck, tc.deck)
+		}
+	}
+}
+
+func TestInvertPow(t *testing.T) {
+	const mod = 10007
+	sh := NewShuffle([]Step{{DealWithIncrement, 7}, {Cut, -2}, {DealIntoNewStack, 0}}, mod)
+	inv, err := sh.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := sh.Pow(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, card := range []int64{0, 1, 2019, mod - 1} {
+		if got := inv.Apply(sh.Apply(card)); got != card {
+			t.Errorf("card %d comes back as %d", card, got)
+		}
+		want := card
+		for i := 0; i < 5; i++ {
+			want = sh.Apply(want)
+		}
+		if got := rep.Apply(card); got != want {
+			t.Errorf("card %d: five shuffles put it at %d, want %d", card, got, want)
+		}
+	}
+	if _, err := NewShuffle([]Step{{DealWithIncrement, 2}}, 10).Invert(); err == nil {
+		t.Error("inverted a shuffle that loses cards")
+	}
+}
+
+func TestHugeDeck(t *testing.T) {
+	const mod = math.MaxInt64 - 24 // prime, near 2^63
+	bm := big.NewInt(mod)
+	steps := []Step{{Cut, mod - 3}, {DealWithIncrement, mod - 2}, {Cut, -(mod - 5)}, {DealIntoNewStack, 0}}
+	sh := NewShuffle(steps, mod)
+	for _, card := range []int64{0, 1, mod / 2, mod - 1} {
+		// shuffle the card the slow way, on big ints
+		want := big.NewInt(card)
+		for _, s := range steps {
+			switch s.Tech {
+			case DealIntoNewStack:
+				want.Sub(big.NewInt(-1), want)
+			case Cut:
+				want.Sub(want, big.NewInt(s.N))
+			case DealWithIncrement:
+				want.Mul(want, big.NewInt(s.N))
+			}
+			want.Mod(want, bm)
+		}
+		if got := sh.Apply(card); got != want.Int64() {
+			t.Errorf("card %d: got %d, want %s", card, got, want)
+		}
+	}
+	if got, want := (Shuffle{mod - 1, mod - 1, mod}).Apply(1), int64(mod-2); got != want {
+		t.Errorf("Apply(1) = %d, want %d", got, want)
+	}
+	inv, err := sh.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := sh.Pow(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, card := range []int64{0, 7, mod - 1} {
+		if got := inv.Apply(sh.Apply(card)); got != card {
+			t.Errorf("card %d comes back as %d", card, got)
+		}
+		if got, want := rep.Apply(card), sh.Apply(sh.Apply(sh.Apply(card))); got != want {
+			t.Errorf("card %d: three shuffles put it at %d, want %d", card, got, want)
+		}
+	}
+}
+
+func TestUnknownTechnique(t *testing.T) {
+	_, err := ReadTransformations(strings.NewReader("cut 3\nriffle\n"))
+	if err == nil || err.Error() != `line 2, col 1: unknown technique "riffle"` {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day23"
+)
+
+func main() {
+	aoc.Main(day23.Part1, day23.Part2)
+}
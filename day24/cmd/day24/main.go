@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day24"
+)
+
+func main() {
+	aoc.MainViz(day24.Part1, day24.Part2, day24.Visualize)
+}
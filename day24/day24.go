@@ -1,12 +1,14 @@
-package main
+// Package day24 simulates the bugs on Eris, on a flat grid and on
+// recursively nested grids.
+package day24
 
 import (
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"strconv"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 type bitset int64
@@ -66,38 +68,38 @@ func (l *layout) next() {
 	l.bits = next
 }
 
-func readLayout(r io.Reader) layout {
-	b := make([]byte, 1)
+func readLayout(r io.Reader) (layout, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return layout{}, err
+	}
+	for len(lines) > 0 && lines[len(lines)-1].Text == "" {
+		lines = lines[:len(lines)-1]
+	}
 	var l layout
 	i := 0
-outer:
-	for {
-	inner:
-		for {
-			_, err := r.Read(b)
-			if err == io.EOF {
-				break outer
-			}
-			if err != nil {
-				log.Fatal(err)
-			}
-			switch b[0] {
-			case '\n':
-				break inner
+	for _, line := range lines {
+		if l.height > 0 && len(line.Text) != l.width {
+			return layout{}, line.Errorf(0, "row has %d cells, want %d", len(line.Text), l.width)
+		}
+		l.width = len(line.Text)
+		for j := 0; j < len(line.Text); j++ {
+			switch c := line.Text[j]; c {
 			case '#':
 				l.bits.set(i, true)
-				i++
 			case '.':
 				l.bits.set(i, false)
-				i++
 			default:
-				log.Fatalf("bad char in layout: %c", b[0])
+				return layout{}, line.Errorf(j, "bad cell %q", c)
 			}
+			i++
 		}
 		l.height++
 	}
-	l.width = i / l.height
-	return l
+	if i == 0 || i > 63 {
+		return layout{}, fmt.Errorf("layout has %d cells, want 1 to 63", i)
+	}
+	return l, nil
 }
 
 func findRepeat(l layout) bitset {
@@ -158,11 +160,11 @@ func toGrid(l layout) grid {
 	g := make(map[tile]bool)
 	for row := 0; row < l.height; row++ {
 		for col := 0; col < l.width; col++ {
-			t := tile{p: geom.Pt2{col, row}, depth: 0}
+			t := tile{p: geom.Pt2{X: col, Y: row}, depth: 0}
 			g[t] = l.alive(row, col)
 		}
 	}
-	delete(g, tile{p: geom.Pt2{2, 2}, depth: 0})
+	delete(g, tile{p: geom.Pt2{X: 2, Y: 2}, depth: 0})
 	return grid{width: l.width, height: l.height, g: g}
 }
 
@@ -185,56 +187,56 @@ func (g grid) adjacent(t tile) []tile {
 
 	// left
 	if t.p.X > 0 && (t.p.X != 3 || t.p.Y != 2) {
-		q := t.p.Go(geom.Left)
+		q := geom.Screen.Go(t.p, geom.Left)
 		adj = append(adj, tile{p: q, depth: t.depth})
 	} else if t.p.X == 0 {
-		q := geom.Pt2{1, 2}
+		q := geom.Pt2{X: 1, Y: 2}
 		adj = append(adj, tile{p: q, depth: t.depth - 1})
 	} else if t.p.X == 3 && t.p.Y == 2 {
 		for y := 0; y < g.height; y++ {
-			q := geom.Pt2{g.width - 1, y}
+			q := geom.Pt2{X: g.width - 1, Y: y}
 			adj = append(adj, tile{p: q, depth: t.depth + 1})
 		}
 	}
 
 	// right
 	if t.p.X < g.width-1 && (t.p.X != 1 || t.p.Y != 2) {
-		q := t.p.Go(geom.Right)
+		q := geom.Screen.Go(t.p, geom.Right)
 		adj = append(adj, tile{p: q, depth: t.depth})
 	} else if t.p.X == g.width-1 {
-		q := geom.Pt2{3, 2}
+		q := geom.Pt2{X: 3, Y: 2}
 		adj = append(adj, tile{p: q, depth: t.depth - 1})
 	} else if t.p.X == 1 && t.p.Y == 2 {
 		for y := 0; y < g.height; y++ {
-			q := geom.Pt2{0, y}
+			q := geom.Pt2{X: 0, Y: y}
 			adj = append(adj, tile{p: q, depth: t.depth + 1})
 		}
 	}
 
-	// down
+	// up
 	if t.p.Y > 0 && (t.p.X != 2 || t.p.Y != 3) {
-		q := t.p.Go(geom.Down)
+		q := geom.Screen.Go(t.p, geom.Up)
 		adj = append(adj, tile{p: q, depth: t.depth})
 	} else if t.p.Y == 0 {
-		q := geom.Pt2{2, 1}
+		q := geom.Pt2{X: 2, Y: 1}
 		adj = append(adj, tile{p: q, depth: t.depth - 1})
 	} else if t.p.X == 2 && t.p.Y == 3 {
 		for x := 0; x < g.width; x++ {
-			q := geom.Pt2{x, g.height - 1}
+			q := geom.Pt2{X: x, Y: g.height - 1}
 			adj = append(adj, tile{p: q, depth: t.depth + 1})
 		}
 	}
 
-	// up
+	// down
 	if t.p.Y < g.height-1 && (t.p.X != 2 || t.p.Y != 1) {
-		q := t.p.Go(geom.Up)
+		q := geom.Screen.Go(t.p, geom.Down)
 		adj = append(adj, tile{p: q, depth: t.depth})
 	} else if t.p.Y == g.height-1 {
-		q := geom.Pt2{2, 3}
+		q := geom.Pt2{X: 2, Y: 3}
 		adj = append(adj, tile{p: q, depth: t.depth - 1})
 	} else if t.p.X == 2 && t.p.Y == 1 {
 		for x := 0; x < g.width; x++ {
-			q := geom.Pt2{x, 0}
+			q := geom.Pt2{X: x, Y: 0}
 			adj = append(adj, tile{p: q, depth: t.depth + 1})
 		}
 	}
@@ -242,17 +244,26 @@ func (g grid) adjacent(t tile) []tile {
 	return adj
 }
 
-func main() {
-	f, err := os.Open(os.Args[1])
+// Part1 returns the biodiversity rating of the first layout that
+// appears twice.
+func Part1(r io.Reader) (string, error) {
+	l, err := readLayout(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	l := readLayout(f)
-	fmt.Println(findRepeat(l))
+	return strconv.FormatInt(int64(findRepeat(l)), 10), nil
+}
 
+// Part2 returns the number of bugs after 200 minutes on the recursive
+// grids.
+func Part2(r io.Reader) (string, error) {
+	l, err := readLayout(r)
+	if err != nil {
+		return "", err
+	}
 	g := toGrid(l)
 	for i := 0; i < 200; i++ {
 		g.next()
 	}
-	fmt.Println(g.countBugs())
+	return strconv.Itoa(g.countBugs()), nil
 }
@@ -0,0 +1,44 @@
+package day24
+
+import (
+	"image/color"
+	"io"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+var palette = color.Palette{color.RGBA{32, 48, 32, 255}, color.RGBA{160, 220, 64, 255}}
+
+func (l *layout) frame() *viz.Frame {
+	f := viz.NewFrame(l.width, l.height)
+	for row := 0; row < l.height; row++ {
+		for col := 0; col < l.width; col++ {
+			if l.alive(row, col) {
+				f.Set(geom.Pt2{X: col, Y: row}, 1)
+			}
+		}
+	}
+	return f
+}
+
+// Visualize records the bugs on the flat grid, one frame per minute,
+// until a layout appears for the second time.
+func Visualize(r io.Reader) (*viz.Recorder, error) {
+	l, err := readLayout(r)
+	if err != nil {
+		return nil, err
+	}
+	rec := viz.NewRecorder(palette)
+	rec.Scale, rec.FPS = 16, 5
+	rec.Add(l.frame())
+	seen := map[bitset]bool{l.bits: true}
+	for {
+		l.next()
+		rec.Add(l.frame())
+		if seen[l.bits] {
+			return rec, nil
+		}
+		seen[l.bits] = true
+	}
+}
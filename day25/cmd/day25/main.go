@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/dhconnelly/advent-of-code-2019/day25"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+var record = flag.String("record", "", "write the exploration's I/O session to this file")
+
+func main() {
+	flag.Parse()
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var session *intcode.Session
+	if *record != "" {
+		session = &intcode.Session{}
+	}
+	g := day25.NewGame(data, os.Stdin, session)
+	err = g.Play(os.Stdout)
+	// keep the recording even if the game failed, to help find out why
+	if session != nil {
+		if err := session.WriteFile(*record); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,33 +1,44 @@
-package main
+// Package day25 lets a player explore the ship in a text adventure to
+// find the password for the airlock. There's no automatic solution; the
+// game has to be played by hand.
+package day25
 
 import (
 	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"os"
 
 	"github.com/dhconnelly/advent-of-code-2019/geom"
 	"github.com/dhconnelly/advent-of-code-2019/intcode"
 )
 
-type game struct {
-	in  chan<- int64
-	out <-chan int64
-	r   *bufio.Scanner
+// Game is a running text adventure.
+type Game struct {
+	in   chan<- int64
+	out  <-chan int64
+	wait func() error
+	r    *bufio.Scanner
 }
 
-func NewGame(data []int64, r io.Reader) game {
+// NewGame starts the adventure, reading the player's commands from r. If
+// s is non-nil, every input and output of the game is recorded to it.
+func NewGame(data []int64, r io.Reader, s *intcode.Session) Game {
 	in := make(chan int64)
-	out := intcode.RunProgram(data, in)
-	return game{in, out, bufio.NewScanner(r)}
+	var out <-chan int64
+	var wait func() error
+	if s != nil {
+		out, wait = intcode.Record(data, in, s)
+	} else {
+		out, wait = intcode.Start(data, in)
+	}
+	return Game{in, out, wait, bufio.NewScanner(r)}
 }
 
-func (g game) readLine() (string, bool) {
+func (g Game) readLine() (string, bool) {
 	var b []byte
 	var ok bool
 	var c int64
-	for c, ok = <-g.out; ok && c != '\n'; c = <-g.out {
+	for c, ok = <-g.out; ok && c != '\n'; c, ok = <-g.out {
 		b = append(b, byte(c))
 	}
 	if len(b) > 0 {
@@ -36,17 +47,17 @@ func (g game) readLine() (string, bool) {
 	return "", ok
 }
 
-func (g game) getCommand() (string, bool) {
+func (g Game) getCommand() (string, bool, error) {
 	if g.r.Scan() {
-		return g.r.Text(), true
+		return g.r.Text(), true, nil
 	}
 	if err := g.r.Err(); err != nil {
-		log.Fatalf("failed to read command: %s", err)
+		return "", false, fmt.Errorf("failed to read command: %w", err)
 	}
-	return "", false
+	return "", false, nil
 }
 
-func (g game) writeLine(line string) {
+func (g Game) writeLine(line string) {
 	for _, c := range line {
 		g.in <- int64(c)
 	}
@@ -69,22 +80,29 @@ func isDir(line string) bool {
 	return false
 }
 
-func (g game) loop() {
+// Play runs the game until it halts or the player runs out of
+// commands, writing the game's output to w. It returns an error if the
+// player's commands can't be read or the program hits an instruction it
+// can't execute.
+func (g Game) Play(w io.Writer) error {
 	for {
 		line, ok := g.readLine()
 		if !ok {
-			fmt.Println("machine halted; exiting")
-			return
+			fmt.Fprintln(w, "machine halted; exiting")
+			return g.wait()
 		}
 		if line != prompt {
-			fmt.Println(line)
+			fmt.Fprintln(w, line)
 			continue
 		}
-		fmt.Println(prompt)
-		cmd, ok := g.getCommand()
+		fmt.Fprintln(w, prompt)
+		cmd, ok, err := g.getCommand()
+		if err != nil {
+			return err
+		}
 		if !ok {
-			fmt.Println("no more commands; exiting")
-			return
+			fmt.Fprintln(w, "no more commands; exiting")
+			return nil
 		}
 		g.writeLine(cmd)
 	}
@@ -93,12 +111,3 @@ func (g game) loop() {
 const (
 	prompt = "Command?"
 )
-
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
-	if err != nil {
-		log.Fatal(err)
-	}
-	g := NewGame(data, os.Stdin)
-	g.loop()
-}
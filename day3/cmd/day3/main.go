@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day3"
+)
+
+func main() {
+	aoc.Main(day3.Part1, day3.Part2)
+}
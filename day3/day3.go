@@ -1,116 +1,106 @@
-package main
+// Package day3 finds where two wires on a grid cross.
+package day3
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
-	"math"
-	"os"
-	"strings"
+	"io"
+	"strconv"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 type vec struct {
-	dir  rune
-	dist int
+	Dir  string
+	Dist int
 }
 
-type coord struct {
-	x, y int
-}
+var vecParser = input.MustParser[vec](`([UDLR])(\d+)`)
 
-func readVecPaths(filePath string) [][]vec {
-	txt, err := ioutil.ReadFile(filePath)
+func readVecPaths(r io.Reader) ([][]vec, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	lines := strings.TrimSpace(string(txt))
 	var vecPaths [][]vec
-	for _, line := range strings.Split(lines, "\n") {
+	for _, line := range lines {
 		var vecPath []vec
-		for _, tok := range strings.Split(line, ",") {
-			var v vec
-			_, err := fmt.Sscanf(tok, "%c%d", &v.dir, &v.dist)
+		for _, tok := range line.Split(",") {
+			v, err := vecParser.Parse(tok)
 			if err != nil {
-				log.Fatalf("failed to parse vec: %s", tok)
+				return nil, err
 			}
 			vecPath = append(vecPath, v)
 		}
 		vecPaths = append(vecPaths, vecPath)
 	}
-	return vecPaths
+	return vecPaths, nil
+}
+
+// A wire is the sequence of segments it runs along, starting at the
+// origin.
+type wire []geom.Segment
+
+var dirs = map[string]geom.Direction{
+	"U": geom.Up,
+	"D": geom.Down,
+	"R": geom.Right,
+	"L": geom.Left,
 }
 
-func toPath(vecPath []vec) []coord {
-	var path []coord
-	var cur coord
+func toWire(vecPath []vec) wire {
+	var w wire
+	h := geom.Heading{Pos: geom.Zero2}
 	for _, v := range vecPath {
-		var dim *int
-		d := 0
-		switch v.dir {
-		case 'U':
-			dim = &cur.y
-			d = +1
-		case 'D':
-			dim = &cur.y
-			d = -1
-		case 'R':
-			dim = &cur.x
-			d = +1
-		case 'L':
-			dim = &cur.x
-			d = -1
-		default:
-			log.Fatalf("bad direction: %s", v.dir)
-		}
-		for n := v.dist; n > 0; n-- {
-			*dim += d
-			path = append(path, cur)
-		}
+		h.Dir = dirs[v.Dir]
+		next := h.Forward(v.Dist)
+		w = append(w, geom.Segment{A: h.Pos, B: next.Pos})
+		h = next
 	}
-	return path
+	return w
 }
 
-func findIntersects(coords1, coords2 []coord) []coord {
-	coords := make(map[coord]bool)
-	for _, c := range coords1 {
-		coords[c] = true
-	}
-	var intersections []coord
-	for _, c := range coords2 {
-		if coords[c] {
-			intersections = append(intersections, c)
+func findIntersects(w1, w2 wire) []geom.Pt2 {
+	seen := make(map[geom.Pt2]bool)
+	var intersections []geom.Pt2
+	for _, s1 := range w1 {
+		for _, s2 := range w2 {
+			for _, p := range s1.Intersections(s2) {
+				if p != geom.Zero2 && !seen[p] {
+					seen[p] = true
+					intersections = append(intersections, p)
+				}
+			}
 		}
 	}
 	return intersections
 }
 
-func dist(c coord) int {
-	return int(math.Abs(float64(c.x)) + math.Abs(float64(c.y)))
-}
-
-func closestIntersect(intersects []coord) int {
+func closestIntersect(intersects []geom.Pt2) int {
 	var closestDist int
-	for _, coord := range intersects {
-		if d := dist(coord); closestDist == 0 || d < closestDist {
+	for _, p := range intersects {
+		if d := p.ManhattanNorm(); closestDist == 0 || d < closestDist {
 			closestDist = d
 		}
 	}
 	return closestDist
 }
 
-func stepsTo(to coord, path []coord) int {
-	for i, cur := range path {
-		if cur == to {
-			return i + 1
+func stepsTo(to geom.Pt2, w wire) int {
+	steps := 0
+	for _, s := range w {
+		if s.Contains(to) {
+			return steps + s.A.ManhattanDist(to)
 		}
+		steps += s.A.ManhattanDist(s.B)
 	}
 	return 0
 }
 
-func fastestIntersect(coords []coord, path1, path2 []coord) int {
+func fastestIntersect(intersects []geom.Pt2, w1, w2 wire) int {
 	var speed int
-	for _, c := range coords {
-		sum := stepsTo(c, path1) + stepsTo(c, path2)
+	for _, p := range intersects {
+		sum := stepsTo(p, w1) + stepsTo(p, w2)
 		if speed == 0 || sum < speed {
 			speed = sum
 		}
@@ -118,10 +108,33 @@ func fastestIntersect(coords []coord, path1, path2 []coord) int {
 	return speed
 }
 
-func main() {
-	vecPaths := readVecPaths(os.Args[1])
-	path1, path2 := toPath(vecPaths[0]), toPath(vecPaths[1])
-	intersects := findIntersects(path1, path2)
-	fmt.Println(closestIntersect(intersects))
-	fmt.Println(fastestIntersect(intersects, path1, path2))
+func readWires(r io.Reader) (wire, wire, error) {
+	vecPaths, err := readVecPaths(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vecPaths) != 2 {
+		return nil, nil, fmt.Errorf("got %d wires, want 2", len(vecPaths))
+	}
+	return toWire(vecPaths[0]), toWire(vecPaths[1]), nil
+}
+
+// Part1 returns the Manhattan distance from the origin to the closest
+// point where the wires cross.
+func Part1(r io.Reader) (string, error) {
+	w1, w2, err := readWires(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(closestIntersect(findIntersects(w1, w2))), nil
+}
+
+// Part2 returns the fewest combined steps the wires take to reach a
+// point where they cross.
+func Part2(r io.Reader) (string, error) {
+	w1, w2, err := readWires(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(fastestIntersect(findIntersects(w1, w2), w1, w2)), nil
 }
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day4"
+)
+
+func main() {
+	aoc.Main(day4.Part1, day4.Part2)
+}
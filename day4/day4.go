@@ -1,9 +1,10 @@
-package main
+// Package day4 counts the passwords in a range that meet the Elves'
+// criteria.
+package day4
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
 	"strconv"
 )
 
@@ -50,14 +51,31 @@ func countValidPasswords(from, to int) (int, int) {
 	return numValid1, numValid2
 }
 
-func main() {
-	from, err := strconv.Atoi(os.Args[1])
+func readRange(r io.Reader) (from, to int, err error) {
+	if _, err := fmt.Fscanf(r, "%d-%d", &from, &to); err != nil {
+		return 0, 0, fmt.Errorf("bad range: %w", err)
+	}
+	return from, to, nil
+}
+
+// Part1 counts the passwords in the range that never decrease and have
+// two adjacent digits the same.
+func Part1(r io.Reader) (string, error) {
+	from, to, err := readRange(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	to, err := strconv.Atoi(os.Args[2])
+	n, _ := countValidPasswords(from, to)
+	return strconv.Itoa(n), nil
+}
+
+// Part2 is like Part1, but the adjacent matching digits must not be part
+// of a larger group.
+func Part2(r io.Reader) (string, error) {
+	from, to, err := readRange(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	fmt.Println(countValidPasswords(from, to))
+	_, n := countValidPasswords(from, to)
+	return strconv.Itoa(n), nil
 }
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day5"
+)
+
+func main() {
+	aoc.Main(day5.Part1, day5.Part2)
+}
@@ -1,12 +1,14 @@
-package main
+// Package day5 runs the thermal environment supervision terminal on an
+// intcode computer with jumps, comparisons and I/O.
+package day5
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
+	"io"
 	"strconv"
-	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/dhconnelly/advent-of-code-2019/ints"
 )
 
 type mode int
@@ -26,137 +28,157 @@ var opcodeToParam = map[int]int{
 	1: 3, 2: 3, 3: 1, 4: 1, 5: 2, 6: 2, 7: 3, 8: 3, 99: 0,
 }
 
-func parseInstr(i int) instr {
+func parseInstr(i int) (instr, error) {
 	var in instr
 	in.opcode = i % 100
-	in.params = opcodeToParam[in.opcode]
-	for i /= 100; len(in.modes) < in.params; i /= 10 {
-		in.modes = append(in.modes, mode(i%10))
+	params, ok := opcodeToParam[in.opcode]
+	if !ok {
+		return in, fmt.Errorf("bad opcode: %d", i)
+	}
+	in.params = params
+	for m := i / 100; len(in.modes) < in.params; m /= 10 {
+		md := mode(m % 10)
+		if md != POS && md != IMM {
+			return in, fmt.Errorf("bad mode: %d", i)
+		}
+		in.modes = append(in.modes, md)
 	}
-	return in
+	return in, nil
 }
 
-func atoi(a string) int {
-	i, err := strconv.Atoi(a)
+func read(r io.Reader) ([]int, error) {
+	prog, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	return i
+	data := make([]int, len(prog))
+	for i, v := range prog {
+		data[i] = int(v)
+	}
+	return data, nil
 }
 
-func read(path string) []int {
-	txt, err := ioutil.ReadFile(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-	toks := strings.Split(strings.TrimSpace(string(txt)), ",")
-	data := make([]int, len(toks))
-	for i, tok := range toks {
-		data[i] = atoi(tok)
+// The operand that each instruction writes to, if it writes.
+var opcodeToWrite = map[int]int{1: 2, 2: 2, 3: 0, 7: 2, 8: 2}
+
+// Returns data[i], or an error if i is outside the program.
+func at(data []int, i int) (int, error) {
+	if i < 0 || i >= len(data) {
+		return 0, fmt.Errorf("address %d outside the program", i)
 	}
-	return data
+	return data[i], nil
 }
 
-func get(data []int, i int, m mode) int {
-	v := data[i]
-	switch m {
-	case POS:
-		return data[v]
-	case IMM:
-		return v
+// Reads the operands of the instruction at pos, whose modes have been
+// checked by parseInstr: the value of each one it reads, and the address
+// of the one it writes.
+func operands(data []int, pos int, in instr) ([]int, error) {
+	w, writes := opcodeToWrite[in.opcode]
+	args := make([]int, in.params)
+	for k := range args {
+		v, err := at(data, pos+1+k)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case writes && k == w:
+			_, err = at(data, v) // the address has to be in the program
+		case in.modes[k] == POS:
+			v, err = at(data, v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		args[k] = v
 	}
-	log.Fatalf("unknown mode: %d", m)
-	return 0
+	return args, nil
 }
 
-func run(data []int, in <-chan int, out chan<- int) {
-	for i := 0; i < len(data); {
-		instr := parseInstr(data[i])
+func run(data []int, in <-chan int, out chan<- int) error {
+	i := 0
+	for 0 <= i && i < len(data) {
+		instr, err := parseInstr(data[i])
+		if err != nil {
+			return fmt.Errorf("pos %d: %w", i, err)
+		}
+		args, err := operands(data, i, instr)
+		if err != nil {
+			return fmt.Errorf("pos %d: %w", i, err)
+		}
+		next := i + instr.params + 1
 		switch instr.opcode {
 		case 1:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			data[s] = l + r
-			i += instr.params + 1
+			data[args[2]] = args[0] + args[1]
 		case 2:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			data[s] = l * r
-			i += instr.params + 1
+			data[args[2]] = args[0] * args[1]
 		case 3:
-			s := data[i+1]
-			data[s] = <-in
-			i += instr.params + 1
+			v, ok := <-in
+			if !ok {
+				return fmt.Errorf("pos %d: out of input", i)
+			}
+			data[args[0]] = v
 		case 4:
-			v := get(data, i+1, instr.modes[0])
-			out <- v
-			i += instr.params + 1
+			out <- args[0]
 		case 5:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			if l != 0 {
-				i = r
-			} else {
-				i += instr.params + 1
+			if args[0] != 0 {
+				next = args[1]
 			}
 		case 6:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			if l == 0 {
-				i = r
-			} else {
-				i += instr.params + 1
+			if args[0] == 0 {
+				next = args[1]
 			}
 		case 7:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			if l < r {
-				data[s] = 1
-			} else {
-				data[s] = 0
+			data[args[2]] = 0
+			if args[0] < args[1] {
+				data[args[2]] = 1
 			}
-			i += instr.params + 1
 		case 8:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			if l == r {
-				data[s] = 1
-			} else {
-				data[s] = 0
+			data[args[2]] = 0
+			if args[0] == args[1] {
+				data[args[2]] = 1
 			}
-			i += instr.params + 1
 		case 99:
-			close(out)
-			return
+			return nil
 		}
+		i = next
 	}
+	return fmt.Errorf("ran off the program at pos %d", i)
 }
 
-func copied(data []int) []int {
-	data2 := make([]int, len(data))
-	copy(data2, data)
-	return data2
-}
-
-func execute(data []int, input int) int {
-	data = copied(data)
+func execute(data []int, input int) (int, error) {
+	data = ints.Copied(data)
 	in, out := make(chan int, 1), make(chan int)
 	in <- input
-	go run(data, in, out)
+	close(in)
+	var err error
+	go func() {
+		err = run(data, in, out)
+		close(out)
+	}()
 	var o int
 	for o = range out {
 	}
-	close(in)
-	return o
+	return o, err
 }
 
-func main() {
-	data := read(os.Args[1])
-	for _, a := range os.Args[2:] {
-		fmt.Println(execute(data, atoi(a)))
+func solve(r io.Reader, input int) (string, error) {
+	data, err := read(r)
+	if err != nil {
+		return "", err
 	}
+	o, err := execute(data, input)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(o), nil
+}
+
+// Part1 returns the diagnostic code for the air conditioner unit, system ID 1.
+func Part1(r io.Reader) (string, error) {
+	return solve(r, 1)
+}
+
+// Part2 returns the diagnostic code for the thermal radiator controller, system ID 5.
+func Part2(r io.Reader) (string, error) {
+	return solve(r, 5)
 }
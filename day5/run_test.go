@@ -0,0 +1,29 @@
+package day5
+
+import (
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode/intcodetest"
+)
+
+// Checks that this day's interpreter agrees with the intcode package.
+func FuzzRun(f *testing.F) {
+	intcodetest.FuzzAgainst(f, intcodetest.Chans(run))
+}
+
+func TestBadInstruction(t *testing.T) {
+	for _, data := range [][]int{
+		{42, 0, 99},      // unknown opcode
+		{304, 0, 99},     // unknown mode
+		{1101, 1, 1, 0},  // no halt
+		{1, 2, 3},        // truncated instruction
+		{1, 0, 0, 7, 99}, // write outside the program
+		{1, 9, 0, 0, 99}, // read outside the program
+		{1105, 1, -3},    // jump outside the program
+		{3, 0, 3, 0, 99}, // more reads than input
+	} {
+		if _, err := execute(data, 1); err == nil {
+			t.Errorf("%v: no error", data)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day6"
+)
+
+func main() {
+	aoc.Main(day6.Part1, day6.Part2)
+}
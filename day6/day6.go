@@ -1,34 +1,31 @@
-package main
+// Package day6 counts orbits in a map of the local orbits.
+package day6
 
 import (
-	"bufio"
-	"fmt"
-	"log"
-	"os"
-	"strings"
+	"io"
+	"strconv"
+
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 type orbit struct {
 	orbiter, orbited string
 }
 
-func read(path string) []orbit {
-	f, err := os.Open(path)
+func read(r io.Reader) ([]orbit, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	defer f.Close()
-	scan := bufio.NewScanner(f)
 	var orbits []orbit
-	for scan.Scan() {
-		line := scan.Text()
-		objs := strings.Split(line, ")")
-		orbits = append(orbits, orbit{objs[1], objs[0]})
-	}
-	if err = scan.Err(); err != nil {
-		log.Fatal(err)
+	for _, line := range lines {
+		orbited, orbiter, err := line.Cut(")")
+		if err != nil {
+			return nil, err
+		}
+		orbits = append(orbits, orbit{orbiter.Text, orbited.Text})
 	}
-	return orbits
+	return orbits, nil
 }
 
 func orbitMap(orbits []orbit) map[string]string {
@@ -74,8 +71,21 @@ func transfers(orbits map[string]string, from, to string) int {
 	return dist1 + dist2
 }
 
-func main() {
-	m := orbitMap(read(os.Args[1]))
-	fmt.Println(countOrbits(m))
-	fmt.Println(transfers(m, "YOU", "SAN"))
+// Part1 returns the total number of direct and indirect orbits.
+func Part1(r io.Reader) (string, error) {
+	orbits, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(countOrbits(orbitMap(orbits))), nil
+}
+
+// Part2 returns the number of orbital transfers needed to get from the
+// object YOU orbit to the object SAN orbits.
+func Part2(r io.Reader) (string, error) {
+	orbits, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(transfers(orbitMap(orbits), "YOU", "SAN")), nil
 }
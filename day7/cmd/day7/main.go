@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day7"
+)
+
+func main() {
+	aoc.Main(day7.Part1, day7.Part2)
+}
@@ -1,12 +1,14 @@
-package main
+// Package day7 finds the phase settings that give the highest signal
+// from a series of intcode amplifiers.
+package day7
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
+	"io"
 	"strconv"
-	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/circuit"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
 )
 
 type mode int
@@ -26,205 +28,135 @@ var opcodeToParam = map[int]int{
 	1: 3, 2: 3, 3: 1, 4: 1, 5: 2, 6: 2, 7: 3, 8: 3, 99: 0,
 }
 
-func parseInstr(i int) instr {
+func parseInstr(i int) (instr, error) {
 	var in instr
 	in.opcode = i % 100
-	in.params = opcodeToParam[in.opcode]
-	for i /= 100; len(in.modes) < in.params; i /= 10 {
-		in.modes = append(in.modes, mode(i%10))
+	params, ok := opcodeToParam[in.opcode]
+	if !ok {
+		return in, fmt.Errorf("bad opcode: %d", i)
 	}
-	return in
-}
-
-func atoi(a string) int {
-	i, err := strconv.Atoi(a)
-	if err != nil {
-		log.Fatal(err)
+	in.params = params
+	for m := i / 100; len(in.modes) < in.params; m /= 10 {
+		md := mode(m % 10)
+		if md != POS && md != IMM {
+			return in, fmt.Errorf("bad mode: %d", i)
+		}
+		in.modes = append(in.modes, md)
 	}
-	return i
+	return in, nil
 }
 
-func read(path string) []int {
-	txt, err := ioutil.ReadFile(path)
-	if err != nil {
-		log.Fatal(err)
-	}
-	toks := strings.Split(strings.TrimSpace(string(txt)), ",")
-	data := make([]int, len(toks))
-	for i, tok := range toks {
-		data[i] = atoi(tok)
+// The operand that each instruction writes to, if it writes.
+var opcodeToWrite = map[int]int{1: 2, 2: 2, 3: 0, 7: 2, 8: 2}
+
+// Returns data[i], or an error if i is outside the program.
+func at(data []int, i int) (int, error) {
+	if i < 0 || i >= len(data) {
+		return 0, fmt.Errorf("address %d outside the program", i)
 	}
-	return data
+	return data[i], nil
 }
 
-func get(data []int, i int, m mode) int {
-	v := data[i]
-	switch m {
-	case POS:
-		return data[v]
-	case IMM:
-		return v
+// Reads the operands of the instruction at pos, whose modes have been
+// checked by parseInstr: the value of each one it reads, and the address
+// of the one it writes.
+func operands(data []int, pos int, in instr) ([]int, error) {
+	w, writes := opcodeToWrite[in.opcode]
+	args := make([]int, in.params)
+	for k := range args {
+		v, err := at(data, pos+1+k)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case writes && k == w:
+			_, err = at(data, v) // the address has to be in the program
+		case in.modes[k] == POS:
+			v, err = at(data, v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		args[k] = v
 	}
-	log.Fatalf("unknown mode: %d", m)
-	return 0
+	return args, nil
 }
 
-func run(data []int, in <-chan int, out chan<- int) {
-	for i := 0; i < len(data); {
-		instr := parseInstr(data[i])
+// The answers come from the circuit package, but this day's own
+// interpreter is kept so it can be fuzzed against the intcode package.
+func run(data []int, in <-chan int, out chan<- int) error {
+	i := 0
+	for 0 <= i && i < len(data) {
+		instr, err := parseInstr(data[i])
+		if err != nil {
+			return fmt.Errorf("pos %d: %w", i, err)
+		}
+		args, err := operands(data, i, instr)
+		if err != nil {
+			return fmt.Errorf("pos %d: %w", i, err)
+		}
+		next := i + instr.params + 1
 		switch instr.opcode {
 		case 1:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			data[s] = l + r
-			i += instr.params + 1
+			data[args[2]] = args[0] + args[1]
 		case 2:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			data[s] = l * r
-			i += instr.params + 1
+			data[args[2]] = args[0] * args[1]
 		case 3:
-			s := data[i+1]
-			data[s] = <-in
-			i += instr.params + 1
+			v, ok := <-in
+			if !ok {
+				return fmt.Errorf("pos %d: out of input", i)
+			}
+			data[args[0]] = v
 		case 4:
-			v := get(data, i+1, instr.modes[0])
-			out <- v
-			i += instr.params + 1
+			out <- args[0]
 		case 5:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			if l != 0 {
-				i = r
-			} else {
-				i += instr.params + 1
+			if args[0] != 0 {
+				next = args[1]
 			}
 		case 6:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			if l == 0 {
-				i = r
-			} else {
-				i += instr.params + 1
+			if args[0] == 0 {
+				next = args[1]
 			}
 		case 7:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			if l < r {
-				data[s] = 1
-			} else {
-				data[s] = 0
+			data[args[2]] = 0
+			if args[0] < args[1] {
+				data[args[2]] = 1
 			}
-			i += instr.params + 1
 		case 8:
-			l := get(data, i+1, instr.modes[0])
-			r := get(data, i+2, instr.modes[1])
-			s := data[i+3]
-			if l == r {
-				data[s] = 1
-			} else {
-				data[s] = 0
+			data[args[2]] = 0
+			if args[0] == args[1] {
+				data[args[2]] = 1
 			}
-			i += instr.params + 1
 		case 99:
-			close(out)
-			return
-		}
-	}
-}
-
-func copied(data []int) []int {
-	data2 := make([]int, len(data))
-	copy(data2, data)
-	return data2
-}
-
-func execute(data []int, phase int, signals <-chan int) chan int {
-	data = copied(data)
-	in, out := make(chan int), make(chan int)
-	go func() {
-		in <- phase
-		for signal := range signals {
-			in <- signal
-		}
-		close(in)
-	}()
-	go run(data, in, out)
-	return out
-}
-
-type seq [5]int
-
-func genSeqsRec(nums []int, used map[int]bool, until int) []seq {
-	if until == 0 {
-		return []seq{{}}
-	}
-	var seqs []seq
-	for _, num := range nums {
-		if !used[num] {
-			used[num] = true
-			for _, recSeq := range genSeqsRec(nums, used, until-1) {
-				recSeq[until-1] = num
-				seqs = append(seqs, recSeq)
-			}
-			used[num] = false
+			return nil
 		}
+		i = next
 	}
-	return seqs
+	return fmt.Errorf("ran off the program at pos %d", i)
 }
 
-func genSeqs(nums []int) []seq {
-	return genSeqsRec(nums, make(map[int]bool), len(nums))
-}
-
-func executeSeq(data []int, s seq) int {
-	out := 0
-	for _, phase := range s {
-		in := make(chan int, 1)
-		in <- out
-		close(in)
-		out = <-execute(data, phase, in)
-	}
-	return out
-}
-
-func executeWithFeedback(data []int, s seq) int {
-	// send the initial input
-	in := make(chan int, 1)
-	in <- 0
-
-	// pipe the amplifiers together
-	out := in
-	for _, phase := range s {
-		out = execute(data, phase, out)
+// maxSignal returns the highest signal that the amplifiers can send when
+// wired up according to top, trying each order of the phase settings.
+func maxSignal(r io.Reader, top circuit.Topology, phases []int64) (string, error) {
+	prog, err := intcode.Parse(r)
+	if err != nil {
+		return "", err
 	}
-
-	// pipe the output back into the input, but keep track of it
-	var o int
-	for o = range out {
-		in <- o
+	_, sig, err := circuit.MaxSignal(prog, top, phases, 0)
+	if err != nil {
+		return "", err
 	}
-
-	// last output is the output signal
-	return o
+	return strconv.FormatInt(sig, 10), nil
 }
 
-func maxSignal(data []int, exec func([]int, seq) int, nums []int) int {
-	max := 0
-	for _, seq := range genSeqs(nums) {
-		out := exec(data, seq)
-		if out > max {
-			max = out
-		}
-	}
-	return max
+// Part1 returns the highest signal the amplifiers can send to the
+// thrusters when wired in series.
+func Part1(r io.Reader) (string, error) {
+	return maxSignal(r, circuit.Series, []int64{0, 1, 2, 3, 4})
 }
 
-func main() {
-	data := read(os.Args[1])
-	fmt.Println(maxSignal(data, executeSeq, []int{0, 1, 2, 3, 4}))
-	fmt.Println(maxSignal(data, executeWithFeedback, []int{5, 6, 7, 8, 9}))
+// Part2 returns the highest signal the amplifiers can send to the
+// thrusters when wired in a feedback loop.
+func Part2(r io.Reader) (string, error) {
+	return maxSignal(r, circuit.Loop, []int64{5, 6, 7, 8, 9})
 }
@@ -0,0 +1,27 @@
+package day7
+
+import (
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode/intcodetest"
+)
+
+// Checks that this day's interpreter agrees with the intcode package.
+func FuzzRun(f *testing.F) {
+	intcodetest.FuzzAgainst(f, intcodetest.Chans(run))
+}
+
+func TestBadInstruction(t *testing.T) {
+	run := intcodetest.Chans(run)
+	for _, prog := range [][]int64{
+		{42, 0, 99},      // unknown opcode
+		{1, 2, 3},        // truncated instruction
+		{1, 0, 0, 7, 99}, // write outside the program
+		{1105, 1, -3},    // jump outside the program
+		{3, 0, 3, 0, 99}, // more reads than input
+	} {
+		if _, err := run(prog, []int64{1}); err == nil {
+			t.Errorf("%v: no error", prog)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day8"
+)
+
+func main() {
+	aoc.MainViz(day8.Part1, day8.Part2, day8.Visualize)
+}
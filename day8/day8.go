@@ -1,10 +1,13 @@
-package main
+// Package day8 decodes images in the Space Image Format.
+package day8
 
 import (
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/input"
 )
 
 type image struct {
@@ -12,29 +15,33 @@ type image struct {
 	layers        [][]byte
 }
 
-func readImage(path string, width, height int) image {
-	f, err := os.Open(path)
+func readImage(r io.Reader, width, height int) (image, error) {
+	lines, err := input.Lines(r)
 	if err != nil {
-		log.Fatal(err)
+		return image{}, err
 	}
-	defer f.Close()
-	img := image{width, height, [][]byte{{}}}
-	b := make([]byte, 1)
-	for {
-		n, err := f.Read(b)
-		if n == 1 && b[0] != '\n' {
-			if len(img.layers[len(img.layers)-1]) == width*height {
-				img.layers = append(img.layers, []byte{})
+	img := image{width, height, nil}
+	var layer []byte
+	for _, line := range lines {
+		for i := 0; i < len(line.Text); i++ {
+			c := line.Text[i]
+			if c < '0' || c > '9' {
+				return image{}, line.Errorf(i, "bad pixel %q", c)
+			}
+			layer = append(layer, c-'0')
+			if len(layer) == width*height {
+				img.layers = append(img.layers, layer)
+				layer = nil
 			}
-			i := len(img.layers) - 1
-			img.layers[i] = append(img.layers[i], b[0]-'0')
-		} else if err == io.EOF {
-			break
-		} else if err != nil {
-			log.Fatal(err)
 		}
 	}
-	return img
+	if len(layer) > 0 {
+		return image{}, fmt.Errorf("image ends partway through layer %d", len(img.layers))
+	}
+	if len(img.layers) == 0 {
+		return image{}, fmt.Errorf("image has no layers")
+	}
+	return img, nil
 }
 
 func zeroes(pixels []byte) int {
@@ -90,22 +97,39 @@ func decode(img image) []byte {
 	return b
 }
 
-func printImage(b []byte, width, height int) {
+func printImage(w io.Writer, b []byte, width, height int) {
 	for i := 0; i < height; i++ {
 		for j := 0; j < width; j++ {
 			pix := b[i*width+j]
 			if pix == 0 {
-				fmt.Printf(" ")
+				fmt.Fprintf(w, " ")
 			} else {
-				fmt.Printf("%d", b[i*width+j])
+				fmt.Fprintf(w, "%d", b[i*width+j])
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
 
-func main() {
-	img := readImage(os.Args[1], 25, 6)
-	fmt.Println(checksum(img))
-	printImage(decode(img), 25, 6)
+const width, height = 25, 6
+
+// Part1 returns the number of 1 digits times the number of 2 digits in
+// the layer with the fewest 0 digits.
+func Part1(r io.Reader) (string, error) {
+	img, err := readImage(r, width, height)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(checksum(img)), nil
+}
+
+// Part2 returns the decoded image, drawn as text.
+func Part2(r io.Reader) (string, error) {
+	img, err := readImage(r, width, height)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	printImage(&b, decode(img), width, height)
+	return strings.TrimSuffix(b.String(), "\n"), nil
 }
@@ -0,0 +1,33 @@
+package day8
+
+import (
+	"image/color"
+	"io"
+
+	"github.com/dhconnelly/advent-of-code-2019/viz"
+)
+
+// Pixel values index into this, so transparent pixels stay transparent.
+var palette = color.Palette{color.Black, color.White, color.Transparent}
+
+// Visualize records the image being decoded, with the layers stacked up
+// one frame at a time from the back.
+func Visualize(r io.Reader) (*viz.Recorder, error) {
+	img, err := readImage(r, width, height)
+	if err != nil {
+		return nil, err
+	}
+	rec := viz.NewRecorder(palette)
+	rec.Scale, rec.FPS = 8, 20
+	b := make([]byte, width*height)
+	for i := range b {
+		b[i] = 2
+	}
+	for i := len(img.layers) - 1; i >= 0; i-- {
+		apply(b, img.layers[i])
+		f := viz.NewFrame(width, height)
+		copy(f.Cells, b)
+		rec.Add(f)
+	}
+	return rec, nil
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/aoc"
+	"github.com/dhconnelly/advent-of-code-2019/day9"
+)
+
+func main() {
+	aoc.Main(day9.Part1, day9.Part2)
+}
@@ -1,31 +1,47 @@
-package main
+// Package day9 runs the BOOST program, which checks the intcode
+// computer's relative mode and large number support.
+package day9
 
 import (
-	"fmt"
-	"github.com/dhconnelly/advent-of-code-2019/intcode"
-	"log"
-	"os"
+	"io"
 	"strconv"
+	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
 )
 
-func run(data []int64, input int64) {
+// Runs the program with the given input and returns its outputs, one
+// per line.
+func run(data []int64, input int64) (string, error) {
 	ch := make(chan int64, 1)
 	ch <- input
-	for o := range intcode.RunProgram(data, ch) {
-		fmt.Println(o)
+	out, wait := intcode.Start(data, ch)
+	var outs []string
+	for o := range out {
+		outs = append(outs, strconv.FormatInt(o, 10))
 	}
+	if err := wait(); err != nil {
+		return "", err
+	}
+	return strings.Join(outs, "\n"), nil
 }
 
-func main() {
-	data, err := intcode.ReadProgram(os.Args[1])
+func solve(r io.Reader, input int64) (string, error) {
+	data, err := intcode.Parse(r)
 	if err != nil {
-		log.Fatal(err)
-	}
-	for _, s := range os.Args[2:] {
-		x, err := strconv.Atoi(s)
-		if err != nil {
-			log.Fatal(err)
-		}
-		run(data, int64(x))
+		return "", err
 	}
+	return run(data, input)
+}
+
+// Part1 runs BOOST in test mode and returns the BOOST keycode, preceded
+// by any opcodes it found to be malfunctioning.
+func Part1(r io.Reader) (string, error) {
+	return solve(r, 1)
+}
+
+// Part2 runs BOOST in sensor boost mode and returns the coordinates of
+// the distress signal.
+func Part2(r io.Reader) (string, error) {
+	return solve(r, 2)
 }
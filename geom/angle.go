@@ -0,0 +1,98 @@
+package geom
+
+import (
+	"sort"
+
+	"github.com/dhconnelly/advent-of-code-2019/ints"
+)
+
+// Reduce divides p by the greatest common divisor of its coordinates,
+// giving the smallest lattice vector pointing the same way. Zero2 is
+// left as is.
+func (p Pt2) Reduce() Pt2 {
+	g := ints.Abs(ints.Gcd(p.X, p.Y))
+	if g == 0 {
+		return p
+	}
+	return Pt2{p.X / g, p.Y / g}
+}
+
+func (p Pt2) Sub(q Pt2) Pt2 {
+	return Pt2{p.X - q.X, p.Y - q.Y}
+}
+
+// Returns 0 for vectors from Up clockwise to just before Down, and 1
+// from Down clockwise to just before Up, in screen coordinates.
+func half(v Pt2) int {
+	if v.X > 0 || (v.X == 0 && v.Y < 0) {
+		return 0
+	}
+	return 1
+}
+
+// ClockwiseLess reports whether vector a comes before vector b when
+// sweeping clockwise from Up, using exact integer arithmetic. Vectors
+// pointing the same way are equal, and Zero2 comes before everything.
+func (s System) ClockwiseLess(a, b Pt2) bool {
+	if s == Cartesian {
+		a.Y, b.Y = -a.Y, -b.Y
+	}
+	if b == Zero2 {
+		return false
+	}
+	if a == Zero2 {
+		return true
+	}
+	if ha, hb := half(a), half(b); ha != hb {
+		return ha < hb
+	}
+	return a.X*b.Y-a.Y*b.X > 0
+}
+
+// LatticeTo returns the integer points on the segment from p to q,
+// inclusive, in order from p.
+func (p Pt2) LatticeTo(q Pt2) []Pt2 {
+	step := q.Sub(p).Reduce()
+	ps := []Pt2{p}
+	for p != q {
+		p = p.Add(step)
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// Sees reports whether none of the integer points strictly between p
+// and q is blocked.
+func (p Pt2) Sees(q Pt2, blocked func(Pt2) bool) bool {
+	ps := p.LatticeTo(q)
+	for i := 1; i < len(ps)-1; i++ {
+		if blocked(ps[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Visible returns the points in pts that can be seen from the given
+// point, i.e. those closest to it in their direction, sorted clockwise
+// starting from Up and ignoring from itself.
+func (s System) Visible(from Pt2, pts []Pt2) []Pt2 {
+	nearest := make(map[Pt2]Pt2)
+	for _, p := range pts {
+		if p == from {
+			continue
+		}
+		d := p.Sub(from).Reduce()
+		if q, ok := nearest[d]; !ok || from.ManhattanDist(p) < from.ManhattanDist(q) {
+			nearest[d] = p
+		}
+	}
+	visible := make([]Pt2, 0, len(nearest))
+	for _, p := range nearest {
+		visible = append(visible, p)
+	}
+	sort.Slice(visible, func(i, j int) bool {
+		return s.ClockwiseLess(visible[i].Sub(from), visible[j].Sub(from))
+	})
+	return visible
+}
@@ -0,0 +1,74 @@
+package geom
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestReduce(t *testing.T) {
+	for _, tc := range []struct{ p, want Pt2 }{
+		{Zero2, Zero2},
+		{Pt2{4, -6}, Pt2{2, -3}},
+		{Pt2{-5, 0}, Pt2{-1, 0}},
+		{Pt2{0, 7}, Pt2{0, 1}},
+		{Pt2{-3, -9}, Pt2{-1, -3}},
+	} {
+		if got := tc.p.Reduce(); got != tc.want {
+			t.Errorf("%v.Reduce() = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestClockwiseLess(t *testing.T) {
+	// screen coordinates, clockwise from up
+	want := []Pt2{
+		{0, -2}, {1, -3}, {1, -1}, {3, -1}, {1, 0}, {2, 1}, {1, 1},
+		{0, 1}, {-1, 2}, {-1, 0}, {-3, -1}, {-1, -1}, {-1, -5},
+	}
+	got := append([]Pt2(nil), want...)
+	for i, j := 0, len(got)-1; i < j; i, j = i+1, j-1 {
+		got[i], got[j] = got[j], got[i]
+	}
+	sort.Slice(got, func(i, j int) bool { return Screen.ClockwiseLess(got[i], got[j]) })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted clockwise = %v, want %v", got, want)
+	}
+	if Screen.ClockwiseLess(Pt2{2, 2}, Pt2{1, 1}) || Screen.ClockwiseLess(Pt2{1, 1}, Pt2{2, 2}) {
+		t.Error("parallel vectors should compare equal")
+	}
+	// in Cartesian coordinates, up is +y
+	if !Cartesian.ClockwiseLess(Pt2{0, 1}, Pt2{1, 0}) || !Cartesian.ClockwiseLess(Pt2{1, 0}, Pt2{0, -1}) {
+		t.Error("Cartesian order should be up, right, down")
+	}
+}
+
+func TestLatticeTo(t *testing.T) {
+	got := Pt2{1, 1}.LatticeTo(Pt2{7, -3})
+	want := []Pt2{{1, 1}, {4, -1}, {7, -3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LatticeTo = %v, want %v", got, want)
+	}
+	if got := Zero2.LatticeTo(Zero2); !reflect.DeepEqual(got, []Pt2{Zero2}) {
+		t.Errorf("LatticeTo(self) = %v", got)
+	}
+}
+
+func TestVisible(t *testing.T) {
+	pts := []Pt2{{0, 0}, {2, 0}, {4, 0}, {0, 3}, {2, 2}, {3, 3}, {0, -1}}
+	blocked := make(map[Pt2]bool)
+	for _, p := range pts {
+		blocked[p] = true
+	}
+	got := Screen.Visible(Zero2, pts)
+	want := []Pt2{{0, -1}, {2, 0}, {2, 2}, {0, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Visible = %v, want %v", got, want)
+	}
+	for _, p := range pts[1:] {
+		sees := Zero2.Sees(p, func(q Pt2) bool { return blocked[q] })
+		if wantSees := p != (Pt2{4, 0}) && p != (Pt2{3, 3}); sees != wantSees {
+			t.Errorf("Sees(%v) = %t, want %t", p, sees, wantSees)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package geom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Grid maps points to cells of type T. A dense grid stores every cell
+// within its bounds, while a sparse grid stores only the cells that have
+// been set. Either way the bounds grow to include every cell set.
+type Grid[T any] struct {
+	dense  []T // row-major over bounds, if dense
+	sparse map[Pt2]T
+	bounds Rect
+	empty  bool
+}
+
+// NewDense returns a grid with a cell for every point in r, each set to
+// the zero value of T. If r has a width or height of zero, with Hi one
+// less than Lo, the grid starts out empty like a new sparse grid. It
+// panics if Hi is any further below Lo.
+func NewDense[T any](r Rect) *Grid[T] {
+	if r.Width() < 0 || r.Height() < 0 {
+		panic(fmt.Sprintf("geom: NewDense of inverted Rect %v", r))
+	}
+	if r.Area() == 0 {
+		return &Grid[T]{dense: []T{}, empty: true}
+	}
+	return &Grid[T]{dense: make([]T, r.Width()*r.Height()), bounds: r}
+}
+
+func NewSparse[T any]() *Grid[T] {
+	return &Grid[T]{sparse: make(map[Pt2]T), empty: true}
+}
+
+func (g *Grid[T]) IsDense() bool {
+	return g.sparse == nil
+}
+
+// Bounds returns the smallest rectangle containing every cell. The
+// bounds of an empty grid are the zero Rect.
+func (g *Grid[T]) Bounds() Rect {
+	return g.bounds
+}
+
+// Len returns the number of cells in the grid.
+func (g *Grid[T]) Len() int {
+	if g.IsDense() {
+		return len(g.dense)
+	}
+	return len(g.sparse)
+}
+
+func (g *Grid[T]) index(p Pt2) int {
+	return (p.Y-g.bounds.Lo.Y)*g.bounds.Width() + p.X - g.bounds.Lo.X
+}
+
+// Get returns the cell at p, if there is one.
+func (g *Grid[T]) Get(p Pt2) (T, bool) {
+	if g.IsDense() {
+		if g.empty || !g.bounds.Contains(p) {
+			var zero T
+			return zero, false
+		}
+		return g.dense[g.index(p)], true
+	}
+	v, ok := g.sparse[p]
+	return v, ok
+}
+
+// At returns the cell at p, or the zero value of T if there isn't one.
+func (g *Grid[T]) At(p Pt2) T {
+	v, _ := g.Get(p)
+	return v
+}
+
+func (g *Grid[T]) grow(p Pt2) {
+	r := Rect{p, p}
+	if !g.empty {
+		r = g.bounds.Union(r)
+	}
+	dense := make([]T, r.Width()*r.Height())
+	old := *g
+	g.dense, g.bounds, g.empty = dense, r, false
+	old.Each(func(q Pt2, v T) {
+		g.dense[g.index(q)] = v
+	})
+}
+
+// Set stores v at p. Setting a dense grid's cell outside its bounds
+// grows the grid, filling the new cells with the zero value of T.
+func (g *Grid[T]) Set(p Pt2, v T) {
+	if g.IsDense() {
+		if g.empty || !g.bounds.Contains(p) {
+			g.grow(p)
+		}
+		g.dense[g.index(p)] = v
+		return
+	}
+	if g.empty {
+		g.bounds, g.empty = Rect{p, p}, false
+	} else if !g.bounds.Contains(p) {
+		g.bounds = g.bounds.Union(Rect{p, p})
+	}
+	g.sparse[p] = v
+}
+
+// Delete removes the cell at p from a sparse grid. In a dense grid, it
+// resets the cell to the zero value of T. The bounds never shrink.
+func (g *Grid[T]) Delete(p Pt2) {
+	if g.IsDense() {
+		if !g.empty && g.bounds.Contains(p) {
+			var zero T
+			g.dense[g.index(p)] = zero
+		}
+		return
+	}
+	delete(g.sparse, p)
+}
+
+// Each calls f for every cell in row-major order.
+func (g *Grid[T]) Each(f func(p Pt2, v T)) {
+	if g.IsDense() {
+		for i, v := range g.dense {
+			w := g.bounds.Width()
+			f(Pt2{g.bounds.Lo.X + i%w, g.bounds.Lo.Y + i/w}, v)
+		}
+		return
+	}
+	ps := make([]Pt2, 0, len(g.sparse))
+	for p := range g.sparse {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Y != ps[j].Y {
+			return ps[i].Y < ps[j].Y
+		}
+		return ps[i].X < ps[j].X
+	})
+	for _, p := range ps {
+		f(p, g.sparse[p])
+	}
+}
+
+// Neighbors returns the orthogonal neighbors of p that are in the grid.
+func (g *Grid[T]) Neighbors(p Pt2) []Pt2 {
+	var nbrs []Pt2
+	for _, nbr := range p.ManhattanNeighbors() {
+		if _, ok := g.Get(nbr); ok {
+			nbrs = append(nbrs, nbr)
+		}
+	}
+	return nbrs
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid[T]) Clone() *Grid[T] {
+	c := *g
+	if g.IsDense() {
+		c.dense = append([]T(nil), g.dense...)
+	} else {
+		c.sparse = make(map[Pt2]T, len(g.sparse))
+		for p, v := range g.sparse {
+			c.sparse[p] = v
+		}
+	}
+	return &c
+}
+
+// Render draws the grid's bounds as text in screen coordinates, one
+// line per row, using f to draw each cell. For points without a cell, f
+// is passed false. An empty grid is drawn as "".
+func (g *Grid[T]) Render(f func(v T, ok bool) rune) string {
+	return g.RenderIn(Screen, f)
+}
+
+// RenderIn is like Render, but draws the rows in the order given by the
+// coordinate system, so that Cartesian grids are drawn with the highest
+// row first.
+func (g *Grid[T]) RenderIn(sys System, f func(v T, ok bool) rune) string {
+	if g.empty {
+		return ""
+	}
+	var b strings.Builder
+	for _, y := range sys.Rows(g.bounds) {
+		for x := g.bounds.Lo.X; x <= g.bounds.Hi.X; x++ {
+			b.WriteRune(f(g.Get(Pt2{x, y})))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		lines = append(lines, strings.TrimRight(scan.Text(), "\r"))
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+// ParseDense reads a rectangular grid of text, with x increasing to the
+// right and y increasing downward from (0, 0) at the top left.
+func ParseDense[T any](r io.Reader, f func(c rune) T) (*Grid[T], error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty grid")
+	}
+	width := len([]rune(lines[0]))
+	g := NewDense[T](Rect{Zero2, Pt2{width - 1, len(lines) - 1}})
+	for y, line := range lines {
+		row := []rune(line)
+		if len(row) != width {
+			return nil, fmt.Errorf("line %d has width %d, want %d", y+1, len(row), width)
+		}
+		for x, c := range row {
+			g.dense[g.index(Pt2{x, y})] = f(c)
+		}
+	}
+	return g, nil
+}
+
+// ParseSparse reads a grid of text like ParseDense, but keeps only the
+// cells for which f returns true. Lines may have different lengths.
+func ParseSparse[T any](r io.Reader, f func(c rune) (T, bool)) (*Grid[T], error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	g := NewSparse[T]()
+	for y, line := range lines {
+		for x, c := range []rune(line) {
+			if v, ok := f(c); ok {
+				g.Set(Pt2{x, y}, v)
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,134 @@
+package geom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const maze = `#####
+#..@#
+#.#.#
+#####
+`
+
+func identity(c rune) rune { return c }
+
+func render(c rune, ok bool) rune {
+	if !ok {
+		return ' '
+	}
+	return c
+}
+
+func TestParseDense(t *testing.T) {
+	g, err := ParseDense(strings.NewReader(maze), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Rect{Zero2, Pt2{4, 3}}); g.Bounds() != want {
+		t.Errorf("Bounds() = %v, want %v", g.Bounds(), want)
+	}
+	if g.Len() != 20 {
+		t.Errorf("Len() = %d, want 20", g.Len())
+	}
+	if c := g.At(Pt2{3, 1}); c != '@' {
+		t.Errorf("At(3, 1) = %c, want @", c)
+	}
+	if _, ok := g.Get(Pt2{5, 1}); ok {
+		t.Error("Get(5, 1) found a cell outside the bounds")
+	}
+	if got := g.Render(render); got != maze {
+		t.Errorf("Render() = %q, want %q", got, maze)
+	}
+}
+
+func TestParseDenseRagged(t *testing.T) {
+	if _, err := ParseDense(strings.NewReader("##\n#\n"), identity); err == nil {
+		t.Error("ParseDense succeeded on a ragged grid")
+	}
+}
+
+func TestParseSparse(t *testing.T) {
+	g, err := ParseSparse(strings.NewReader(maze), func(c rune) (rune, bool) {
+		return c, c != '#'
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Rect{Pt2{1, 1}, Pt2{3, 2}}); g.Bounds() != want {
+		t.Errorf("Bounds() = %v, want %v", g.Bounds(), want)
+	}
+	if got, want := g.Neighbors(Pt2{3, 1}), []Pt2{{3, 2}, {2, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbors(3, 1) = %v, want %v", got, want)
+	}
+	if got, want := g.Render(render), "..@\n. .\n"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestGridSet(t *testing.T) {
+	for _, g := range []*Grid[int]{NewDense[int](Rect{Zero2, Zero2}), NewSparse[int]()} {
+		g.Set(Pt2{2, -1}, 5)
+		g.Set(Pt2{-1, 1}, 7)
+		if want := (Rect{Pt2{-1, -1}, Pt2{2, 1}}); g.Bounds() != want {
+			t.Errorf("Bounds() = %v, want %v", g.Bounds(), want)
+		}
+		if g.At(Pt2{2, -1}) != 5 || g.At(Pt2{-1, 1}) != 7 {
+			t.Errorf("lost cells after growing: %v", g)
+		}
+		var ps []Pt2
+		g.Each(func(p Pt2, v int) {
+			if v != 0 {
+				ps = append(ps, p)
+			}
+		})
+		if want := []Pt2{{2, -1}, {-1, 1}}; !reflect.DeepEqual(ps, want) {
+			t.Errorf("Each visited %v, want %v", ps, want)
+		}
+		c := g.Clone()
+		g.Delete(Pt2{2, -1})
+		if g.At(Pt2{2, -1}) != 0 || c.At(Pt2{2, -1}) != 5 {
+			t.Errorf("Delete affected the clone")
+		}
+	}
+}
+
+func TestEmptyDense(t *testing.T) {
+	g := NewDense[int](Rect{Pt2{3, 3}, Pt2{2, 5}})
+	if g.Len() != 0 || g.Bounds() != (Rect{}) {
+		t.Errorf("empty grid has %d cells in %v", g.Len(), g.Bounds())
+	}
+	if _, ok := g.Get(Zero2); ok {
+		t.Error("empty grid has a cell at the origin")
+	}
+	for _, e := range []*Grid[int]{g, NewSparse[int]()} {
+		if got := e.RenderIn(Cartesian, func(int, bool) rune { return '#' }); got != "" {
+			t.Errorf("empty grid rendered as %q", got)
+		}
+	}
+	if h := Transformed(g, Transform{Turns: 1}); h.Len() != 0 {
+		t.Errorf("transformed empty grid has %d cells", h.Len())
+	}
+	g.Set(Pt2{4, 4}, 1)
+	if g.Len() != 1 || g.Bounds() != (Rect{Pt2{4, 4}, Pt2{4, 4}}) {
+		t.Errorf("after Set: %d cells in %v", g.Len(), g.Bounds())
+	}
+}
+
+func TestInvertedDense(t *testing.T) {
+	for _, r := range []Rect{
+		{Pt2{3, 0}, Pt2{1, 5}},
+		{Pt2{0, 3}, Pt2{5, 1}},
+		{Pt2{3, 3}, Pt2{1, 1}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewDense(%v) didn't panic", r)
+				}
+			}()
+			NewDense[int](r)
+		}()
+	}
+}
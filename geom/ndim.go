@@ -0,0 +1,77 @@
+package geom
+
+import "github.com/dhconnelly/advent-of-code-2019/ints"
+
+// Coords is satisfied by fixed-size integer arrays, which serve as
+// points in any number of dimensions from one to eight. Like Pt2 and
+// Pt3 they are comparable and so can be used as map keys.
+type Coords interface {
+	~[1]int | ~[2]int | ~[3]int | ~[4]int | ~[5]int | ~[6]int | ~[7]int | ~[8]int
+}
+
+func AddN[P Coords](p, q P) P {
+	for i := 0; i < len(p); i++ {
+		p[i] += q[i]
+	}
+	return p
+}
+
+func ManhattanDistN[P Coords](p, q P) int {
+	d := 0
+	for i := 0; i < len(p); i++ {
+		d += ints.Abs(p[i] - q[i])
+	}
+	return d
+}
+
+// ChebyshevDistN returns the largest difference along any axis, which
+// is the number of king's moves between the points.
+func ChebyshevDistN[P Coords](p, q P) int {
+	d := 0
+	for i := 0; i < len(p); i++ {
+		d = ints.Max(d, ints.Abs(p[i]-q[i]))
+	}
+	return d
+}
+
+func SquaredDistN[P Coords](p, q P) int {
+	d := 0
+	for i := 0; i < len(p); i++ {
+		d += (p[i] - q[i]) * (p[i] - q[i])
+	}
+	return d
+}
+
+// OrthogonalNeighborsN returns the 2N points differing from p by one
+// along a single axis.
+func OrthogonalNeighborsN[P Coords](p P) []P {
+	var nbrs []P
+	for i := 0; i < len(p); i++ {
+		for _, d := range []int{-1, 1} {
+			q := p
+			q[i] += d
+			nbrs = append(nbrs, q)
+		}
+	}
+	return nbrs
+}
+
+// AllNeighborsN returns the 3^N-1 points differing from p by at most
+// one along every axis, in lexicographic order.
+func AllNeighborsN[P Coords](p P) []P {
+	nbrs := []P{p}
+	for i := 0; i < len(p); i++ {
+		next := make([]P, 0, 3*len(nbrs))
+		for _, q := range nbrs {
+			for _, d := range []int{-1, 0, 1} {
+				r := q
+				r[i] += d
+				next = append(next, r)
+			}
+		}
+		nbrs = next
+	}
+	// p itself is in the middle
+	mid := len(nbrs) / 2
+	return append(nbrs[:mid], nbrs[mid+1:]...)
+}
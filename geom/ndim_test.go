@@ -0,0 +1,71 @@
+package geom
+
+import "testing"
+
+func TestNeighborCounts(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		nbrs int
+		want int
+	}{
+		{"Pt2.ManhattanNeighbors", len(Zero2.ManhattanNeighbors()), 4},
+		{"Pt2.Neighbors8", len(Zero2.Neighbors8()), 8},
+		{"Pt3.Neighbors6", len(Zero3.Neighbors6()), 6},
+		{"Pt3.Neighbors26", len(Zero3.Neighbors26()), 26},
+		{"Pt4.Neighbors8", len(Zero4.Neighbors8()), 8},
+		{"Pt4.Neighbors80", len(Zero4.Neighbors80()), 80},
+		{"AllNeighborsN([5]int)", len(AllNeighborsN([5]int{})), 242},
+	} {
+		if tc.nbrs != tc.want {
+			t.Errorf("%s: got %d neighbors, want %d", tc.name, tc.nbrs, tc.want)
+		}
+	}
+}
+
+func TestNeighborDistances(t *testing.T) {
+	p := Pt3{1, -2, 3}
+	seen := make(map[Pt3]bool)
+	for _, q := range p.Neighbors26() {
+		if d := p.ChebyshevDist(q); d != 1 {
+			t.Errorf("ChebyshevDist(%v, %v) = %d, want 1", p, q, d)
+		}
+		seen[q] = true
+	}
+	if len(seen) != 26 || seen[p] {
+		t.Errorf("Neighbors26(%v) has duplicates or includes itself", p)
+	}
+	for _, q := range p.Neighbors6() {
+		if d := p.ManhattanDist(q); d != 1 {
+			t.Errorf("ManhattanDist(%v, %v) = %d, want 1", p, q, d)
+		}
+	}
+	want := []Pt2{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+	for i, q := range Zero2.Neighbors8() {
+		if q != want[i] {
+			t.Errorf("Neighbors8()[%d] = %v, want %v", i, q, want[i])
+		}
+	}
+}
+
+func TestDistances(t *testing.T) {
+	p, q := Pt2{-3, 5}, Pt2{4, -7}
+	if d := p.ChebyshevDist(q); d != 12 {
+		t.Errorf("ChebyshevDist = %d, want 12", d)
+	}
+	if d := p.SquaredDist(q); d != 193 {
+		t.Errorf("SquaredDist = %d, want 193", d)
+	}
+	r, s := Pt4{1, 2, 3, 4}, Pt4{-1, 2, 7, 4}
+	if d := r.ManhattanDist(s); d != 6 {
+		t.Errorf("Pt4 ManhattanDist = %d, want 6", d)
+	}
+	if d := r.ChebyshevDist(s); d != 4 {
+		t.Errorf("Pt4 ChebyshevDist = %d, want 4", d)
+	}
+	if d := r.SquaredDist(s); d != 20 {
+		t.Errorf("Pt4 SquaredDist = %d, want 20", d)
+	}
+	if got, want := AddN([3]int{1, 2, 3}, [3]int{-1, 1, 0}), ([3]int{0, 3, 3}); got != want {
+		t.Errorf("AddN = %v, want %v", got, want)
+	}
+}
@@ -50,6 +50,8 @@ const (
 	Left  Direction = '<'
 )
 
+// Directions maps each direction to its unit vector in Cartesian
+// coordinates. See System for screen coordinates.
 var Directions = map[Direction]Pt2{
 	Up:    Pt2{0, 1},
 	Down:  Pt2{0, -1},
@@ -57,29 +59,44 @@ var Directions = map[Direction]Pt2{
 	Right: Pt2{1, 0},
 }
 
+// DirectionTo returns the direction from one point to another in
+// Cartesian coordinates, so that from.Go(from.DirectionTo(to)) is a step
+// toward to.
 func (from Pt2) DirectionTo(to Pt2) Direction {
-	switch {
-	case from.X < to.X:
-		return Right
-	case to.X < from.X:
-		return Left
-	case from.Y < to.Y:
-		return Down
-	case to.Y < from.Y:
-		return Up
-	}
-	return 0
+	return Cartesian.DirectionTo(from, to)
 }
 
+// Go moves p one step in the given direction in Cartesian coordinates.
 func (p Pt2) Go(dir Direction) Pt2 {
-	return p.Add(Directions[dir])
+	return Cartesian.Go(p, dir)
 }
 
 func (p Pt2) ManhattanNeighbors() []Pt2 {
-	return []Pt2{
-		p.Add(Directions[Up]),
-		p.Add(Directions[Down]),
-		p.Add(Directions[Left]),
-		p.Add(Directions[Right]),
+	return Cartesian.Neighbors(p)
+}
+
+func (p Pt2) coords() [2]int {
+	return [2]int{p.X, p.Y}
+}
+
+func pt2(c [2]int) Pt2 {
+	return Pt2{c[0], c[1]}
+}
+
+// Neighbors8 returns the eight points orthogonally or diagonally
+// adjacent to p.
+func (p Pt2) Neighbors8() []Pt2 {
+	var nbrs []Pt2
+	for _, c := range AllNeighborsN(p.coords()) {
+		nbrs = append(nbrs, pt2(c))
 	}
+	return nbrs
+}
+
+func (p Pt2) ChebyshevDist(q Pt2) int {
+	return ChebyshevDistN(p.coords(), q.coords())
+}
+
+func (p Pt2) SquaredDist(q Pt2) int {
+	return SquaredDistN(p.coords(), q.coords())
 }
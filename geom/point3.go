@@ -33,3 +33,37 @@ func (p Pt3) ManhattanNorm() int {
 func (p1 Pt3) Eq(p2 Pt3) bool {
 	return p1.X == p2.X && p1.Y == p2.Y && p1.Z == p2.Z
 }
+
+func (p Pt3) coords() [3]int {
+	return [3]int{p.X, p.Y, p.Z}
+}
+
+func pt3s(cs [][3]int) []Pt3 {
+	ps := make([]Pt3, len(cs))
+	for i, c := range cs {
+		ps[i] = Pt3{c[0], c[1], c[2]}
+	}
+	return ps
+}
+
+func (p1 Pt3) ManhattanDist(p2 Pt3) int {
+	return ManhattanDistN(p1.coords(), p2.coords())
+}
+
+func (p1 Pt3) ChebyshevDist(p2 Pt3) int {
+	return ChebyshevDistN(p1.coords(), p2.coords())
+}
+
+func (p1 Pt3) SquaredDist(p2 Pt3) int {
+	return SquaredDistN(p1.coords(), p2.coords())
+}
+
+// Neighbors6 returns the points sharing a face with p.
+func (p Pt3) Neighbors6() []Pt3 {
+	return pt3s(OrthogonalNeighborsN(p.coords()))
+}
+
+// Neighbors26 returns the points sharing a face, edge or corner with p.
+func (p Pt3) Neighbors26() []Pt3 {
+	return pt3s(AllNeighborsN(p.coords()))
+}
@@ -0,0 +1,51 @@
+package geom
+
+type Pt4 struct {
+	X, Y, Z, W int
+}
+
+var Zero4 Pt4
+
+func (p Pt4) coords() [4]int {
+	return [4]int{p.X, p.Y, p.Z, p.W}
+}
+
+func pt4s(cs [][4]int) []Pt4 {
+	ps := make([]Pt4, len(cs))
+	for i, c := range cs {
+		ps[i] = Pt4{c[0], c[1], c[2], c[3]}
+	}
+	return ps
+}
+
+func (p1 Pt4) Add(p2 Pt4) Pt4 {
+	return Pt4{p1.X + p2.X, p1.Y + p2.Y, p1.Z + p2.Z, p1.W + p2.W}
+}
+
+func (p Pt4) ManhattanNorm() int {
+	return p.ManhattanDist(Zero4)
+}
+
+func (p1 Pt4) ManhattanDist(p2 Pt4) int {
+	return ManhattanDistN(p1.coords(), p2.coords())
+}
+
+func (p1 Pt4) ChebyshevDist(p2 Pt4) int {
+	return ChebyshevDistN(p1.coords(), p2.coords())
+}
+
+func (p1 Pt4) SquaredDist(p2 Pt4) int {
+	return SquaredDistN(p1.coords(), p2.coords())
+}
+
+// Neighbors8 returns the points differing from p by one along a single
+// axis.
+func (p Pt4) Neighbors8() []Pt4 {
+	return pt4s(OrthogonalNeighborsN(p.coords()))
+}
+
+// Neighbors80 returns the points differing from p by at most one along
+// every axis.
+func (p Pt4) Neighbors80() []Pt4 {
+	return pt4s(AllNeighborsN(p.coords()))
+}
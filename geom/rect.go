@@ -1,5 +1,7 @@
 package geom
 
+import "github.com/dhconnelly/advent-of-code-2019/ints"
+
 type Rect struct {
 	Lo, Hi Pt2 // inclusive
 }
@@ -7,3 +9,76 @@ type Rect struct {
 func (r Rect) Contains(p Pt2) bool {
 	return p.X >= r.Lo.X && p.Y >= r.Lo.Y && p.X <= r.Hi.X && p.Y <= r.Hi.Y
 }
+
+func (r Rect) Width() int {
+	return r.Hi.X - r.Lo.X + 1
+}
+
+func (r Rect) Height() int {
+	return r.Hi.Y - r.Lo.Y + 1
+}
+
+// Area returns the number of points in r.
+func (r Rect) Area() int {
+	if r.Width() <= 0 || r.Height() <= 0 {
+		return 0
+	}
+	return r.Width() * r.Height()
+}
+
+// Union returns the smallest rectangle containing both r and s.
+func (r Rect) Union(s Rect) Rect {
+	return Rect{
+		Pt2{ints.Min(r.Lo.X, s.Lo.X), ints.Min(r.Lo.Y, s.Lo.Y)},
+		Pt2{ints.Max(r.Hi.X, s.Hi.X), ints.Max(r.Hi.Y, s.Hi.Y)},
+	}
+}
+
+// Intersect returns the points in both r and s, if there are any.
+func (r Rect) Intersect(s Rect) (Rect, bool) {
+	i := Rect{
+		Pt2{ints.Max(r.Lo.X, s.Lo.X), ints.Max(r.Lo.Y, s.Lo.Y)},
+		Pt2{ints.Min(r.Hi.X, s.Hi.X), ints.Min(r.Hi.Y, s.Hi.Y)},
+	}
+	return i, i.Area() > 0
+}
+
+// Expand grows r by n in every direction, or shrinks it if n is
+// negative.
+func (r Rect) Expand(n int) Rect {
+	return Rect{Pt2{r.Lo.X - n, r.Lo.Y - n}, Pt2{r.Hi.X + n, r.Hi.Y + n}}
+}
+
+// Points returns every point in r in row-major order.
+func (r Rect) Points() []Pt2 {
+	ps := make([]Pt2, 0, r.Area())
+	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
+		for x := r.Lo.X; x <= r.Hi.X; x++ {
+			ps = append(ps, Pt2{x, y})
+		}
+	}
+	return ps
+}
+
+// Edges returns the sides of r: the rows at Lo.Y and Hi.Y, running from
+// Lo.X to Hi.X, and the columns at Lo.X and Hi.X, running from Lo.Y to
+// Hi.Y.
+func (r Rect) Edges() []Segment {
+	return []Segment{
+		{r.Lo, Pt2{r.Hi.X, r.Lo.Y}},
+		{Pt2{r.Lo.X, r.Hi.Y}, r.Hi},
+		{r.Lo, Pt2{r.Lo.X, r.Hi.Y}},
+		{Pt2{r.Hi.X, r.Lo.Y}, r.Hi},
+	}
+}
+
+// Border returns the points on the edges of r in row-major order.
+func (r Rect) Border() []Pt2 {
+	var ps []Pt2
+	for _, p := range r.Points() {
+		if p.X == r.Lo.X || p.X == r.Hi.X || p.Y == r.Lo.Y || p.Y == r.Hi.Y {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
@@ -0,0 +1,85 @@
+package geom
+
+import "github.com/dhconnelly/advent-of-code-2019/ints"
+
+// Segment is the line segment between two points, inclusive.
+type Segment struct {
+	A, B Pt2
+}
+
+func cross(p, q Pt2) int {
+	return p.X*q.Y - p.Y*q.X
+}
+
+func dot(p, q Pt2) int {
+	return p.X*q.X + p.Y*q.Y
+}
+
+func (s Segment) IsAxisAligned() bool {
+	return s.A.X == s.B.X || s.A.Y == s.B.Y
+}
+
+// Bounds returns the smallest rectangle containing s.
+func (s Segment) Bounds() Rect {
+	return Rect{s.A, s.A}.Union(Rect{s.B, s.B})
+}
+
+// Points returns the integer points on s in order from s.A.
+func (s Segment) Points() []Pt2 {
+	return s.A.LatticeTo(s.B)
+}
+
+// Contains reports whether p lies on s.
+func (s Segment) Contains(p Pt2) bool {
+	return cross(p.Sub(s.A), s.B.Sub(s.A)) == 0 && s.Bounds().Contains(p)
+}
+
+// Intersections returns the integer points lying on both s and t, in
+// order from s.A. Collinear segments that overlap share every lattice
+// point in the overlap; crossing segments share at most one, and none
+// if they cross between lattice points.
+func (s Segment) Intersections(t Segment) []Pt2 {
+	if s.A == s.B {
+		if t.Contains(s.A) {
+			return []Pt2{s.A}
+		}
+		return nil
+	}
+	if t.A == t.B {
+		if s.Contains(t.A) {
+			return []Pt2{t.A}
+		}
+		return nil
+	}
+
+	d1, d2, ac := s.B.Sub(s.A), t.B.Sub(t.A), t.A.Sub(s.A)
+	if den := cross(d1, d2); den != 0 {
+		// solve s.A + (tn/den)*d1 = t.A + (un/den)*d2
+		tn, un := cross(ac, d2), cross(ac, d1)
+		if den < 0 {
+			den, tn, un = -den, -tn, -un
+		}
+		if tn < 0 || tn > den || un < 0 || un > den {
+			return nil
+		}
+		if (d1.X*tn)%den != 0 || (d1.Y*tn)%den != 0 {
+			return nil
+		}
+		return []Pt2{s.A.Add(Pt2{d1.X * tn / den, d1.Y * tn / den})}
+	}
+	if cross(ac, d1) != 0 {
+		return nil // parallel
+	}
+
+	// collinear: every lattice point on the line is s.A + k*step
+	step := d1.Reduce()
+	n := dot(d1, step) / dot(step, step)
+	kc := dot(ac, step) / dot(step, step)
+	kd := dot(t.B.Sub(s.A), step) / dot(step, step)
+	lo, hi := ints.Max(0, ints.Min(kc, kd)), ints.Min(n, ints.Max(kc, kd))
+	var ps []Pt2
+	for k := lo; k <= hi; k++ {
+		ps = append(ps, s.A.Add(Pt2{k * step.X, k * step.Y}))
+	}
+	return ps
+}
@@ -0,0 +1,91 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRect(t *testing.T) {
+	r := Rect{Pt2{0, 0}, Pt2{3, 2}}
+	s := Rect{Pt2{2, 1}, Pt2{5, 5}}
+	if got := r.Area(); got != 12 {
+		t.Errorf("Area() = %d, want 12", got)
+	}
+	if got, want := r.Union(s), (Rect{Pt2{0, 0}, Pt2{5, 5}}); got != want {
+		t.Errorf("Union() = %v, want %v", got, want)
+	}
+	if got, ok := r.Intersect(s); !ok || got != (Rect{Pt2{2, 1}, Pt2{3, 2}}) {
+		t.Errorf("Intersect() = %v, %t", got, ok)
+	}
+	if _, ok := r.Intersect(Rect{Pt2{4, 0}, Pt2{5, 1}}); ok {
+		t.Errorf("Intersect() of disjoint rects succeeded")
+	}
+	if got, want := r.Expand(1), (Rect{Pt2{-1, -1}, Pt2{4, 3}}); got != want {
+		t.Errorf("Expand(1) = %v, want %v", got, want)
+	}
+	if got := r.Expand(-2).Area(); got != 0 {
+		t.Errorf("Expand(-2).Area() = %d, want 0", got)
+	}
+	small := Rect{Pt2{0, 0}, Pt2{2, 2}}
+	want := []Pt2{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	if got := small.Border(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Border() = %v, want %v", got, want)
+	}
+	n := 0
+	for _, e := range small.Edges() {
+		n += len(e.Points())
+	}
+	if n != 12 {
+		t.Errorf("Edges() cover %d points, want 12", n)
+	}
+}
+
+func TestSegmentIntersections(t *testing.T) {
+	for _, tc := range []struct {
+		s, t Segment
+		want []Pt2
+	}{
+		// crossing
+		{Segment{Pt2{0, 2}, Pt2{4, 2}}, Segment{Pt2{3, 0}, Pt2{3, 5}}, []Pt2{{3, 2}}},
+		{Segment{Pt2{0, 0}, Pt2{4, 4}}, Segment{Pt2{0, 4}, Pt2{4, 0}}, []Pt2{{2, 2}}},
+		// touching at an end
+		{Segment{Pt2{0, 0}, Pt2{2, 0}}, Segment{Pt2{2, 0}, Pt2{2, 3}}, []Pt2{{2, 0}}},
+		// crossing between lattice points
+		{Segment{Pt2{0, 0}, Pt2{1, 1}}, Segment{Pt2{0, 1}, Pt2{1, 0}}, nil},
+		// missing
+		{Segment{Pt2{0, 0}, Pt2{2, 0}}, Segment{Pt2{3, -1}, Pt2{3, 1}}, nil},
+		// parallel
+		{Segment{Pt2{0, 0}, Pt2{2, 0}}, Segment{Pt2{0, 1}, Pt2{2, 1}}, nil},
+		// overlapping, in either direction
+		{Segment{Pt2{0, 0}, Pt2{5, 0}}, Segment{Pt2{7, 0}, Pt2{3, 0}}, []Pt2{{3, 0}, {4, 0}, {5, 0}}},
+		{Segment{Pt2{5, 0}, Pt2{0, 0}}, Segment{Pt2{3, 0}, Pt2{7, 0}}, []Pt2{{5, 0}, {4, 0}, {3, 0}}},
+		{Segment{Pt2{0, 0}, Pt2{6, 3}}, Segment{Pt2{2, 1}, Pt2{8, 4}}, []Pt2{{2, 1}, {4, 2}, {6, 3}}},
+		// collinear but disjoint
+		{Segment{Pt2{0, 0}, Pt2{1, 0}}, Segment{Pt2{3, 0}, Pt2{4, 0}}, nil},
+		// degenerate
+		{Segment{Pt2{1, 1}, Pt2{1, 1}}, Segment{Pt2{0, 0}, Pt2{2, 2}}, []Pt2{{1, 1}}},
+		{Segment{Pt2{0, 0}, Pt2{2, 2}}, Segment{Pt2{1, 0}, Pt2{1, 0}}, nil},
+	} {
+		if got := tc.s.Intersections(tc.t); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v.Intersections(%v) = %v, want %v", tc.s, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestSegmentContains(t *testing.T) {
+	s := Segment{Pt2{0, 0}, Pt2{4, 2}}
+	for _, tc := range []struct {
+		p    Pt2
+		want bool
+	}{
+		{Pt2{0, 0}, true},
+		{Pt2{2, 1}, true},
+		{Pt2{4, 2}, true},
+		{Pt2{1, 1}, false},
+		{Pt2{6, 3}, false},
+	} {
+		if got := s.Contains(tc.p); got != tc.want {
+			t.Errorf("Contains(%v) = %t, want %t", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package geom
+
+// System is a convention for which way the y axis points. Directions
+// are named as they appear visually, so the vector for Up depends on
+// the system in use.
+type System int
+
+const (
+	// Cartesian coordinates have y growing upward. This is the
+	// convention used by Directions, Pt2.Go and Pt2.DirectionTo.
+	Cartesian System = iota
+	// Screen coordinates have y growing downward, as in text grids
+	// read line by line.
+	Screen
+)
+
+// Vec returns the unit vector pointing in the given direction.
+func (s System) Vec(d Direction) Pt2 {
+	v := Directions[d]
+	if s == Screen {
+		v.Y = -v.Y
+	}
+	return v
+}
+
+// Go moves p one step in the given direction.
+func (s System) Go(p Pt2, d Direction) Pt2 {
+	return p.Add(s.Vec(d))
+}
+
+// DirectionTo returns the direction from one point to another, giving
+// priority to horizontal movement, or None if they are equal.
+func (s System) DirectionTo(from, to Pt2) Direction {
+	switch {
+	case from.X < to.X:
+		return Right
+	case to.X < from.X:
+		return Left
+	case from.Y == to.Y:
+		return None
+	case (from.Y < to.Y) == (s == Cartesian):
+		return Up
+	}
+	return Down
+}
+
+// Neighbors returns the orthogonal neighbors of p in the order up,
+// down, left, right.
+func (s System) Neighbors(p Pt2) []Pt2 {
+	return []Pt2{
+		s.Go(p, Up),
+		s.Go(p, Down),
+		s.Go(p, Left),
+		s.Go(p, Right),
+	}
+}
+
+// Rows returns the y coordinates of r's rows from top to bottom.
+func (s System) Rows(r Rect) []int {
+	ys := make([]int, 0, r.Height())
+	for y := r.Lo.Y; y <= r.Hi.Y; y++ {
+		ys = append(ys, y)
+	}
+	if s == Cartesian {
+		for i, j := 0, len(ys)-1; i < j; i, j = i+1, j-1 {
+			ys[i], ys[j] = ys[j], ys[i]
+		}
+	}
+	return ys
+}
@@ -0,0 +1,86 @@
+package geom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSystemVec(t *testing.T) {
+	for _, tc := range []struct {
+		sys  System
+		dir  Direction
+		want Pt2
+	}{
+		{Cartesian, Up, Pt2{0, 1}},
+		{Cartesian, Down, Pt2{0, -1}},
+		{Cartesian, Left, Pt2{-1, 0}},
+		{Cartesian, Right, Pt2{1, 0}},
+		{Screen, Up, Pt2{0, -1}},
+		{Screen, Down, Pt2{0, 1}},
+		{Screen, Left, Pt2{-1, 0}},
+		{Screen, Right, Pt2{1, 0}},
+	} {
+		if got := tc.sys.Vec(tc.dir); got != tc.want {
+			t.Errorf("%d.Vec(%c) = %v, want %v", tc.sys, tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestDirectionTo(t *testing.T) {
+	for _, sys := range []System{Cartesian, Screen} {
+		for _, d := range []Direction{Up, Down, Left, Right} {
+			to := sys.Go(Zero2, d).Add(sys.Go(Zero2, d))
+			if got := sys.DirectionTo(Zero2, to); got != d {
+				t.Errorf("%d.DirectionTo(%v) = %c, want %c", sys, to, got, d)
+			}
+		}
+		if got := sys.DirectionTo(Zero2, Zero2); got != None {
+			t.Errorf("%d.DirectionTo(self) = %c, want none", sys, got)
+		}
+	}
+	// Pt2's methods agree with Directions
+	for d, v := range Directions {
+		if got := Zero2.DirectionTo(v); got != d {
+			t.Errorf("DirectionTo(%v) = %c, want %c", v, got, d)
+		}
+		if got := Zero2.Go(d); got != v {
+			t.Errorf("Go(%c) = %v, want %v", d, got, v)
+		}
+	}
+}
+
+func TestSystemNeighbors(t *testing.T) {
+	want := []Pt2{{1, 0}, {1, 2}, {0, 1}, {2, 1}}
+	if got := Screen.Neighbors(Pt2{1, 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Screen.Neighbors = %v, want %v", got, want)
+	}
+	want[0], want[1] = want[1], want[0]
+	if got := Cartesian.Neighbors(Pt2{1, 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cartesian.Neighbors = %v, want %v", got, want)
+	}
+}
+
+func TestRenderIn(t *testing.T) {
+	g, err := ParseDense(strings.NewReader("ab\ncd\n"), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.RenderIn(Screen, render), "ab\ncd\n"; got != want {
+		t.Errorf("RenderIn(Screen) = %q, want %q", got, want)
+	}
+	if got, want := g.RenderIn(Cartesian, render), "cd\nab\n"; got != want {
+		t.Errorf("RenderIn(Cartesian) = %q, want %q", got, want)
+	}
+	// moving up from the bottom row lands on the top row on screen
+	if c := g.At(Screen.Go(Pt2{0, 1}, Up)); c != 'a' {
+		t.Errorf("Screen up from c = %c, want a", c)
+	}
+}
+
+func TestScreenHeading(t *testing.T) {
+	h := Heading{Dir: Up, Sys: Screen}.Forward(2).TurnRight().Forward(1)
+	if want := (Pt2{1, -2}); h.Pos != want {
+		t.Errorf("heading at %v, want %v", h.Pos, want)
+	}
+}
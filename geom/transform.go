@@ -0,0 +1,153 @@
+package geom
+
+// TurnLeft returns the direction a quarter turn counterclockwise from d.
+func (d Direction) TurnLeft() Direction {
+	switch d {
+	case Up:
+		return Left
+	case Left:
+		return Down
+	case Down:
+		return Right
+	case Right:
+		return Up
+	}
+	return None
+}
+
+// TurnRight returns the direction a quarter turn clockwise from d.
+func (d Direction) TurnRight() Direction {
+	switch d {
+	case Up:
+		return Right
+	case Right:
+		return Down
+	case Down:
+		return Left
+	case Left:
+		return Up
+	}
+	return None
+}
+
+func (d Direction) Reverse() Direction {
+	return d.TurnLeft().TurnLeft()
+}
+
+// Rotate90 rotates p a quarter turn counterclockwise about the given
+// point, so that rotating Directions[d] about Zero2 gives
+// Directions[d.TurnLeft()].
+func (p Pt2) Rotate90(about Pt2) Pt2 {
+	return Pt2{about.X - (p.Y - about.Y), about.Y + (p.X - about.X)}
+}
+
+// Rotate rotates p by the given number of quarter turns counterclockwise
+// about the given point. Negative turns rotate clockwise.
+func (p Pt2) Rotate(about Pt2, turns int) Pt2 {
+	for turns = (turns%4 + 4) % 4; turns > 0; turns-- {
+		p = p.Rotate90(about)
+	}
+	return p
+}
+
+// ReflectX mirrors p across the vertical line through the given point.
+func (p Pt2) ReflectX(about Pt2) Pt2 {
+	return Pt2{2*about.X - p.X, p.Y}
+}
+
+// ReflectY mirrors p across the horizontal line through the given point.
+func (p Pt2) ReflectY(about Pt2) Pt2 {
+	return Pt2{p.X, 2*about.Y - p.Y}
+}
+
+// Transform is one of the eight symmetries of the square: a rotation by
+// some number of quarter turns counterclockwise, optionally preceded by
+// a reflection across the vertical axis.
+type Transform struct {
+	Turns   int
+	Reflect bool
+}
+
+// Transforms lists all eight symmetries, starting with the identity.
+var Transforms = []Transform{
+	{0, false}, {1, false}, {2, false}, {3, false},
+	{0, true}, {1, true}, {2, true}, {3, true},
+}
+
+// Apply transforms p about the origin.
+func (t Transform) Apply(p Pt2) Pt2 {
+	if t.Reflect {
+		p = p.ReflectX(Zero2)
+	}
+	return p.Rotate(Zero2, t.Turns)
+}
+
+// Transformed returns a copy of g with t applied to every cell, moved so
+// that the bounds of the result have the same low corner as g's.
+func Transformed[T any](g *Grid[T], t Transform) *Grid[T] {
+	if g.empty {
+		return g.Clone()
+	}
+	var h *Grid[T]
+	if g.IsDense() {
+		r := Rect{t.Apply(g.bounds.Lo), t.Apply(g.bounds.Lo)}
+		r = r.Union(Rect{t.Apply(g.bounds.Hi), t.Apply(g.bounds.Hi)})
+		h = NewDense[T](r)
+	} else {
+		h = NewSparse[T]()
+	}
+	g.Each(func(p Pt2, v T) {
+		h.Set(t.Apply(p), v)
+	})
+	if h.Len() == 0 {
+		return h
+	}
+	return Translated(h, Pt2{g.bounds.Lo.X - h.bounds.Lo.X, g.bounds.Lo.Y - h.bounds.Lo.Y})
+}
+
+// Translated returns a copy of g with every cell moved by d.
+func Translated[T any](g *Grid[T], d Pt2) *Grid[T] {
+	h := g.Clone()
+	if g.empty {
+		return h
+	}
+	if g.IsDense() {
+		h.bounds = Rect{g.bounds.Lo.Add(d), g.bounds.Hi.Add(d)}
+		return h
+	}
+	h = NewSparse[T]()
+	g.Each(func(p Pt2, v T) {
+		h.Set(p.Add(d), v)
+	})
+	return h
+}
+
+// Heading is a position and the direction it faces, in the given
+// coordinate system.
+type Heading struct {
+	Pos Pt2
+	Dir Direction
+	Sys System
+}
+
+// Forward moves n steps in the direction faced.
+func (h Heading) Forward(n int) Heading {
+	d := h.Sys.Vec(h.Dir)
+	h.Pos = h.Pos.Add(Pt2{n * d.X, n * d.Y})
+	return h
+}
+
+func (h Heading) TurnLeft() Heading {
+	h.Dir = h.Dir.TurnLeft()
+	return h
+}
+
+func (h Heading) TurnRight() Heading {
+	h.Dir = h.Dir.TurnRight()
+	return h
+}
+
+func (h Heading) Reverse() Heading {
+	h.Dir = h.Dir.Reverse()
+	return h
+}
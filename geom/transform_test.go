@@ -0,0 +1,85 @@
+package geom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTurns(t *testing.T) {
+	for _, d := range []Direction{Up, Down, Left, Right} {
+		if got, want := Directions[d.TurnLeft()], Directions[d].Rotate90(Zero2); got != want {
+			t.Errorf("%c.TurnLeft() moves by %v, want %v", d, got, want)
+		}
+		if got := d.TurnLeft().TurnRight(); got != d {
+			t.Errorf("%c.TurnLeft().TurnRight() = %c", d, got)
+		}
+		if got, want := Directions[d.Reverse()], Directions[d].Rotate(Zero2, 2); got != want {
+			t.Errorf("%c.Reverse() moves by %v, want %v", d, got, want)
+		}
+	}
+	if got := Up.TurnRight(); got != Right {
+		t.Errorf("Up.TurnRight() = %c, want %c", got, Right)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	about := Pt2{1, 1}
+	for _, tc := range []struct {
+		turns int
+		want  Pt2
+	}{
+		{0, Pt2{3, 2}},
+		{1, Pt2{0, 3}},
+		{2, Pt2{-1, 0}},
+		{3, Pt2{2, -1}},
+		{-1, Pt2{2, -1}},
+		{5, Pt2{0, 3}},
+	} {
+		if got := (Pt2{3, 2}).Rotate(about, tc.turns); got != tc.want {
+			t.Errorf("Rotate(%v, %d) = %v, want %v", about, tc.turns, got, tc.want)
+		}
+	}
+}
+
+func TestReflect(t *testing.T) {
+	p, about := Pt2{3, 2}, Pt2{1, 1}
+	if got, want := p.ReflectX(about), (Pt2{-1, 2}); got != want {
+		t.Errorf("ReflectX = %v, want %v", got, want)
+	}
+	if got, want := p.ReflectY(about), (Pt2{3, 0}); got != want {
+		t.Errorf("ReflectY = %v, want %v", got, want)
+	}
+}
+
+func TestTransformed(t *testing.T) {
+	g, err := ParseDense(strings.NewReader("ab\ncd\nef\n"), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, tr := range Transforms {
+		h := Transformed(g, tr)
+		if h.Bounds().Lo != g.Bounds().Lo || h.Len() != 6 {
+			t.Errorf("%v: bounds %v, len %d", tr, h.Bounds(), h.Len())
+		}
+		seen[h.Render(render)] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("got %d distinct transforms, want 8", len(seen))
+	}
+	// y grows downward in text, so a counterclockwise turn in math
+	// coordinates is a clockwise turn on screen
+	if got, want := Transformed(g, Transform{Turns: 1}).Render(render), "eca\nfdb\n"; got != want {
+		t.Errorf("quarter turn = %q, want %q", got, want)
+	}
+	if got, want := Transformed(g, Transform{Reflect: true}).Render(render), "ba\ndc\nfe\n"; got != want {
+		t.Errorf("reflection = %q, want %q", got, want)
+	}
+}
+
+func TestHeading(t *testing.T) {
+	h := Heading{Dir: Up}.Forward(3).TurnRight().Forward(2).Reverse().Forward(5)
+	if want := (Heading{Pos: Pt2{-3, 3}, Dir: Left}); h != want {
+		t.Errorf("heading = %v, want %v", h, want)
+	}
+}
@@ -1,5 +1,13 @@
 module github.com/dhconnelly/advent-of-code-2019
 
-go 1.13
+go 1.18
 
 require github.com/gdamore/tcell v1.3.0
+
+require (
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.0.2 // indirect
+	github.com/mattn/go-runewidth v0.0.4 // indirect
+	golang.org/x/sys v0.0.0-20190626150813-e07cf5db2756 // indirect
+	golang.org/x/text v0.3.0 // indirect
+)
@@ -0,0 +1,192 @@
+// Package input parses puzzle input, reporting errors with the line and
+// column at which they occur.
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Error is a problem with the input at a particular position. Lines and
+// columns count from 1, and columns are in bytes. Either may be zero if
+// it isn't known.
+type Error struct {
+	Line, Col int
+	Err       error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Col > 0:
+		return fmt.Sprintf("line %d, col %d: %s", e.Line, e.Col, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	case e.Col > 0:
+		return fmt.Sprintf("col %d: %s", e.Col, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// A Field is a piece of the input along with where it starts, so that
+// errors can point at it.
+type Field struct {
+	Text      string
+	Line, Col int
+}
+
+// Errorf returns an error at the given byte offset into the field.
+func (f Field) Errorf(offset int, format string, args ...interface{}) error {
+	return &Error{f.Line, f.Col + offset, fmt.Errorf(format, args...)}
+}
+
+// Trim removes leading and trailing whitespace.
+func (f Field) Trim() Field {
+	text := strings.TrimLeft(f.Text, " \t\r\n")
+	f.Col += len(f.Text) - len(text)
+	f.Text = strings.TrimRight(text, " \t\r\n")
+	return f
+}
+
+// Split slices the field around each instance of sep and trims the
+// pieces.
+func (f Field) Split(sep string) []Field {
+	var fs []Field
+	for _, tok := range strings.Split(f.Text, sep) {
+		fs = append(fs, Field{tok, f.Line, f.Col}.Trim())
+		f.Col += len(tok) + len(sep)
+	}
+	return fs
+}
+
+// Cut slices the field around the first instance of sep and trims the
+// pieces. If sep doesn't appear, it returns an error.
+func (f Field) Cut(sep string) (before, after Field, err error) {
+	i := strings.Index(f.Text, sep)
+	if i < 0 {
+		return Field{}, Field{}, f.Errorf(0, "missing %q in %q", sep, f.Text)
+	}
+	before = Field{f.Text[:i], f.Line, f.Col}.Trim()
+	after = Field{f.Text[i+len(sep):], f.Line, f.Col + i + len(sep)}.Trim()
+	return before, after, nil
+}
+
+// Int parses the whole field, ignoring surrounding whitespace, as a
+// signed integer.
+func (f Field) Int() (int, error) {
+	f = f.Trim()
+	v, err := strconv.Atoi(f.Text)
+	if err != nil {
+		return 0, f.Errorf(0, "bad int %q", f.Text)
+	}
+	return v, nil
+}
+
+var intRegexp = regexp.MustCompile(`[-+]?\d+`)
+
+// Ints returns all the signed integers in the field, ignoring anything
+// in between them.
+func (f Field) Ints() ([]int, error) {
+	var vs []int
+	for _, loc := range intRegexp.FindAllStringIndex(f.Text, -1) {
+		v, err := strconv.Atoi(f.Text[loc[0]:loc[1]])
+		if err != nil {
+			return nil, f.Errorf(loc[0], "bad int %q", f.Text[loc[0]:loc[1]])
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// Ints returns all the signed integers in s.
+func Ints(s string) ([]int, error) {
+	return Field{Text: s, Col: 1}.Ints()
+}
+
+// Scanner reads input a line at a time.
+type Scanner struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func NewScanner(r io.Reader) *Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	return &Scanner{s: s}
+}
+
+// Scan advances to the next line, returning false at the end of the
+// input or if reading fails.
+func (s *Scanner) Scan() bool {
+	if !s.s.Scan() {
+		return false
+	}
+	s.line++
+	return true
+}
+
+// Field returns the current line without its line ending.
+func (s *Scanner) Field() Field {
+	return Field{strings.TrimSuffix(s.s.Text(), "\r"), s.line, 1}
+}
+
+// Err returns the error that stopped Scan, if any.
+func (s *Scanner) Err() error {
+	if err := s.s.Err(); err != nil {
+		return &Error{Line: s.line + 1, Err: err}
+	}
+	return nil
+}
+
+// Lines reads every line of the input. A final line ending is optional.
+func Lines(r io.Reader) ([]Field, error) {
+	var lines []Field
+	s := NewScanner(r)
+	for s.Scan() {
+		lines = append(lines, s.Field())
+	}
+	return lines, s.Err()
+}
+
+// RecordScanner reads records made of consecutive non-blank lines,
+// separated by one or more blank lines.
+type RecordScanner struct {
+	s      *Scanner
+	record []Field
+}
+
+func NewRecordScanner(r io.Reader) *RecordScanner {
+	return &RecordScanner{s: NewScanner(r)}
+}
+
+// Scan advances to the next record, returning false at the end of the
+// input or if reading fails.
+func (s *RecordScanner) Scan() bool {
+	s.record = nil
+	for s.s.Scan() {
+		f := s.s.Field()
+		if strings.TrimSpace(f.Text) != "" {
+			s.record = append(s.record, f)
+		} else if len(s.record) > 0 {
+			return true
+		}
+	}
+	return len(s.record) > 0
+}
+
+// Record returns the lines of the current record.
+func (s *RecordScanner) Record() []Field {
+	return s.record
+}
+
+// Err returns the error that stopped Scan, if any.
+func (s *RecordScanner) Err() error {
+	return s.s.Err()
+}
@@ -0,0 +1,65 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	got := Field{"R75, D30,U83", 2, 1}.Split(",")
+	want := []Field{{"R75", 2, 1}, {"D30", 2, 6}, {"U83", 2, 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Split = %v, want %v", got, want)
+	}
+}
+
+func TestCut(t *testing.T) {
+	before, after, err := Field{"7 A, 1 B => 1 C", 3, 1}.Cut("=>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Field{"7 A, 1 B", 3, 1}); before != want {
+		t.Errorf("before = %v, want %v", before, want)
+	}
+	if want := (Field{"1 C", 3, 13}); after != want {
+		t.Errorf("after = %v, want %v", after, want)
+	}
+	if _, _, err := (Field{"COM", 4, 1}).Cut(")"); err == nil || err.Error() != `line 4, col 1: missing ")" in "COM"` {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestInts(t *testing.T) {
+	got, err := Ints("<x=-1, y=0, z=+2>")
+	if err != nil || !reflect.DeepEqual(got, []int{-1, 0, 2}) {
+		t.Errorf("Ints = %v, %v", got, err)
+	}
+	_, err = Field{"x=1, y=99999999999999999999", 5, 1}.Ints()
+	if err == nil || err.Error() != `line 5, col 8: bad int "99999999999999999999"` {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := (Field{"cut 12x", 1, 1}).Split(" ")[1].Int(); err == nil || err.Error() != `line 1, col 5: bad int "12x"` {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLines(t *testing.T) {
+	got, err := Lines(strings.NewReader("a\r\n\nb"))
+	want := []Field{{"a", 1, 1}, {"", 2, 1}, {"b", 3, 1}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestRecordScanner(t *testing.T) {
+	s := NewRecordScanner(strings.NewReader("\na\nb\n\n\nc\n"))
+	var got [][]Field
+	for s.Scan() {
+		got = append(got, s.Record())
+	}
+	want := [][]Field{{{"a", 2, 1}, {"b", 3, 1}}, {{"c", 6, 1}}}
+	if s.Err() != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %v, %v, want %v", got, s.Err(), want)
+	}
+}
@@ -0,0 +1,143 @@
+package input
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// Parser fills in structs of type T from text matching a regular
+// expression. A named group fills in the field with the same name, and
+// otherwise the i-th group fills in the i-th field. Fields may be
+// strings, integers or floats.
+type Parser[T any] struct {
+	re     *regexp.Regexp
+	fields []int // field index for each group, or -1
+}
+
+// NewParser compiles pattern, which must match the whole of the text
+// being parsed, and checks that its groups fit T.
+func NewParser[T any](pattern string) (*Parser[T], error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't parse into %s", t)
+	}
+	p := &Parser[T]{re: re, fields: make([]int, re.NumSubexp()+1)}
+	p.fields[0] = -1
+	for i, name := range re.SubexpNames()[1:] {
+		var f reflect.StructField
+		ok := false
+		if name != "" {
+			f, ok = t.FieldByName(name)
+			ok = ok && len(f.Index) == 1
+		} else if i < t.NumField() {
+			f, ok = t.Field(i), true
+		}
+		if !ok || !f.IsExported() {
+			return nil, fmt.Errorf("no exported field in %s for group %d", t, i+1)
+		}
+		if !settable(f.Type.Kind()) {
+			return nil, fmt.Errorf("can't parse into %s.%s of type %s", t, f.Name, f.Type)
+		}
+		p.fields[i+1] = f.Index[0]
+	}
+	return p, nil
+}
+
+// MustParser is like NewParser but panics if the pattern doesn't compile
+// or doesn't fit T.
+func MustParser[T any](pattern string) *Parser[T] {
+	p, err := NewParser[T](pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func settable(k reflect.Kind) bool {
+	switch k {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func set(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+	}
+	return nil
+}
+
+// Parse fills in a T from the field, which must match the whole pattern.
+// Errors point at the group that couldn't be converted.
+func (p *Parser[T]) Parse(f Field) (T, error) {
+	var t T
+	m := p.re.FindStringSubmatchIndex(f.Text)
+	if m == nil {
+		return t, f.Errorf(0, "%q doesn't match %s", f.Text, p.re)
+	}
+	v := reflect.ValueOf(&t).Elem()
+	for i := 1; i < len(p.fields); i++ {
+		start, end := m[2*i], m[2*i+1]
+		if start < 0 {
+			continue
+		}
+		fv := v.Field(p.fields[i])
+		if err := set(fv, f.Text[start:end]); err != nil {
+			return t, f.Errorf(start, "bad %s %q", fv.Type(), f.Text[start:end])
+		}
+	}
+	return t, nil
+}
+
+// ParseString is like Parse for text that isn't part of a larger input.
+func (p *Parser[T]) ParseString(s string) (T, error) {
+	return p.Parse(Field{Text: s, Col: 1})
+}
+
+// ParseLines parses each non-blank line of the input.
+func (p *Parser[T]) ParseLines(r io.Reader) ([]T, error) {
+	var ts []T
+	s := NewScanner(r)
+	for s.Scan() {
+		f := s.Field()
+		if f.Trim().Text == "" {
+			continue
+		}
+		t, err := p.Parse(f)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	return ts, s.Err()
+}
@@ -0,0 +1,62 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type move struct {
+	Dir  string
+	Dist int
+}
+
+type moon struct {
+	Name    string
+	X, Y, Z int64
+}
+
+func TestParser(t *testing.T) {
+	p := MustParser[move](`([UDLR])(\d+)`)
+	got, err := p.ParseString("R75")
+	if want := (move{"R", 75}); err != nil || got != want {
+		t.Errorf("Parse = %v, %v, want %v", got, err, want)
+	}
+	if _, err := p.Parse(Field{"X1", 7, 3}); err == nil || err.Error() != `line 7, col 3: "X1" doesn't match ^(?:([UDLR])(\d+))$` {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := p.Parse(Field{"U99999999999999999999", 7, 3}); err == nil || err.Error() != `line 7, col 4: bad int "99999999999999999999"` {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestParserNamed(t *testing.T) {
+	p := MustParser[moon](`<x=(?P<X>-?\d+), y=(?P<Y>-?\d+), z=(?P<Z>-?\d+)>`)
+	got, err := p.ParseLines(strings.NewReader("<x=-1, y=0, z=2>\n\n<x=2, y=-10, z=-7>\n"))
+	want := []moon{{"", -1, 0, 2}, {"", 2, -10, -7}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLines = %v, %v, want %v", got, err, want)
+	}
+	_, err = p.ParseLines(strings.NewReader("<x=-1, y=0, z=2>\n<x=2, y=-10>\n"))
+	if e, ok := err.(*Error); !ok || e.Line != 2 || e.Col != 1 {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestNewParserErrors(t *testing.T) {
+	if _, err := NewParser[move](`(a)(b)(c)`); err == nil {
+		t.Error("too many groups: no error")
+	}
+	if _, err := NewParser[move](`(?P<Speed>\d+)`); err == nil {
+		t.Error("unknown name: no error")
+	}
+	if _, err := NewParser[struct{ X []int }](`(\d+)`); err == nil {
+		t.Error("bad field type: no error")
+	}
+	if _, err := NewParser[int](`(\d+)`); err == nil {
+		t.Error("not a struct: no error")
+	}
+	if _, err := NewParser[struct{ x int }](`(\d+)`); err == nil {
+		t.Error("unexported field: no error")
+	}
+}
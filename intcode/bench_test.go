@@ -0,0 +1,78 @@
+package intcode
+
+import (
+	"testing"
+	"time"
+)
+
+// A machine whose first cells point at the ones after them, so that
+// every addressing mode reads from memory that exists.
+func benchMachine() *machine {
+	m := newMachine([]int64{2, 3, 4, 5, 6, 7, 8, 9}, false)
+	m.relbase = 1
+	return m
+}
+
+var sink int64
+
+func BenchmarkGet(b *testing.B) {
+	for _, md := range []Mode{pos, imm, rel} {
+		b.Run(md.String(), func(b *testing.B) {
+			m := benchMachine()
+			for i := 0; i < b.N; i++ {
+				sink += m.get(int64(i&3), md)
+			}
+		})
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	for _, md := range []Mode{pos, rel} {
+		b.Run(md.String(), func(b *testing.B) {
+			m := benchMachine()
+			for i := 0; i < b.N; i++ {
+				m.set(int64(i&3), int64(i), md)
+			}
+		})
+	}
+}
+
+// A countdown loop of two instructions per iteration, so that decoding
+// and dispatch dominate:
+//
+//	0: add [9], -1 -> [9]
+//	4: jnz [9], 0
+//	7: halt
+var countdownLoop = []int64{1001, 9, -1, 9, 1005, 9, 0, 99, 0, 0}
+
+func BenchmarkDispatch(b *testing.B) {
+	prog := append([]int64(nil), countdownLoop...)
+	prog[9] = int64(b.N+1) / 2
+	vm := NewMachine(prog)
+	b.ResetTimer()
+	start := time.Now()
+	if state := vm.Run(); state != Halted || vm.Err() != nil {
+		b.Fatalf("machine %s: %v", state, vm.Err())
+	}
+	b.ReportMetric(float64(time.Since(start))/float64(vm.Steps()), "ns/instr")
+}
+
+// Runs the day 9 BOOST program in sensor boost mode, a realistic mix of
+// instructions that takes a few hundred thousand steps.
+func BenchmarkBoost(b *testing.B) {
+	data, err := ReadProgram("../day9/input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var steps int64
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		vm := NewMachine(data)
+		vm.Push(2)
+		if state := vm.Run(); state != Halted || vm.Err() != nil {
+			b.Fatalf("machine %s: %v", state, vm.Err())
+		}
+		steps += vm.Steps()
+	}
+	b.ReportMetric(float64(time.Since(start))/float64(steps), "ns/instr")
+}
@@ -0,0 +1,244 @@
+package intcode
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigMachine runs an intcode program on arbitrary-precision integers, so
+// that arithmetic never overflows. It is much slower than Machine but
+// useful for validating results that might not fit in an int64. It
+// supports only the built-in instructions, and addresses must still fit
+// in an int64.
+type BigMachine struct {
+	pc, relbase int64
+	data        map[int64]*big.Int
+	state       State
+	steps       int64
+	inq, out    []*big.Int
+	err         error
+}
+
+func NewBigMachine(data []int64) *BigMachine {
+	vm := &BigMachine{data: make(map[int64]*big.Int)}
+	for i, v := range data {
+		vm.data[int64(i)] = big.NewInt(v)
+	}
+	return vm
+}
+
+func (vm *BigMachine) Push(vals ...*big.Int) {
+	for _, v := range vals {
+		vm.inq = append(vm.inq, new(big.Int).Set(v))
+	}
+}
+
+func (vm *BigMachine) PushInt64(vals ...int64) {
+	for _, v := range vals {
+		vm.inq = append(vm.inq, big.NewInt(v))
+	}
+}
+
+// Outputs returns the values written since the last call.
+func (vm *BigMachine) Outputs() []*big.Int {
+	out := vm.out
+	vm.out = nil
+	return out
+}
+
+func (vm *BigMachine) State() State {
+	return vm.state
+}
+
+// Steps returns the number of instructions executed so far.
+func (vm *BigMachine) Steps() int64 {
+	return vm.steps
+}
+
+// Err returns the reason the machine halted, if it wasn't a halt
+// instruction.
+func (vm *BigMachine) Err() error {
+	return vm.err
+}
+
+// Mem returns the value stored at addr.
+func (vm *BigMachine) Mem(addr int64) *big.Int {
+	return new(big.Int).Set(vm.at(addr))
+}
+
+func (vm *BigMachine) at(addr int64) *big.Int {
+	if v, ok := vm.data[addr]; ok {
+		return v
+	}
+	return new(big.Int)
+}
+
+func (vm *BigMachine) addr(v *big.Int, offset int64) (int64, error) {
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("bad address at pos %d: %s", vm.pc, v)
+	}
+	return v.Int64() + offset, nil
+}
+
+func (vm *BigMachine) get(i int64, md Mode) (*big.Int, error) {
+	v := vm.at(vm.pc + i + 1)
+	switch md {
+	case imm:
+		return v, nil
+	case pos, rel:
+		var offset int64
+		if md == rel {
+			offset = vm.relbase
+		}
+		addr, err := vm.addr(v, offset)
+		if err != nil {
+			return nil, err
+		}
+		return vm.at(addr), nil
+	}
+	return nil, vm.badInstr(ErrBadMode)
+}
+
+func (vm *BigMachine) set(i int64, md Mode, val *big.Int) error {
+	v := vm.at(vm.pc + i + 1)
+	var offset int64
+	switch md {
+	case pos:
+	case rel:
+		offset = vm.relbase
+	default:
+		return vm.badInstr(ErrBadMode)
+	}
+	addr, err := vm.addr(v, offset)
+	if err != nil {
+		return err
+	}
+	vm.data[addr] = val
+	return nil
+}
+
+func (vm *BigMachine) badInstr(err error) error {
+	return &InstructionError{vm.pc, vm.at(vm.pc).Int64(), err}
+}
+
+// Gets the values of the first n operands.
+func (vm *BigMachine) args(instr instruction, n int) ([]*big.Int, error) {
+	args := make([]*big.Int, n)
+	for i := range args {
+		v, err := vm.get(int64(i), instr.modes[i])
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+func (vm *BigMachine) exec() error {
+	v := vm.at(vm.pc)
+	if !v.IsInt64() {
+		return fmt.Errorf("bad instr at pos %d: %s", vm.pc, v)
+	}
+	instr := parseInstruction(v.Int64())
+	info, ok := builtins[instr.op]
+	if !ok {
+		return vm.badInstr(ErrBadOpcode)
+	}
+	instr.arity = info.arity
+	for int64(len(instr.modes)) < instr.arity {
+		instr.modes = append(instr.modes, pos)
+	}
+	next := vm.pc + instr.arity + 1
+
+	switch instr.op {
+	case add, mul, lt, eq:
+		args, err := vm.args(instr, 2)
+		if err != nil {
+			return err
+		}
+		val := new(big.Int)
+		switch cmp := args[0].Cmp(args[1]); instr.op {
+		case add:
+			val.Add(args[0], args[1])
+		case mul:
+			val.Mul(args[0], args[1])
+		case lt:
+			if cmp < 0 {
+				val.SetInt64(1)
+			}
+		case eq:
+			if cmp == 0 {
+				val.SetInt64(1)
+			}
+		}
+		if err := vm.set(2, instr.modes[2], val); err != nil {
+			return err
+		}
+
+	case read:
+		if len(vm.inq) == 0 {
+			vm.state = Blocked
+			return nil
+		}
+		if err := vm.set(0, instr.modes[0], vm.inq[0]); err != nil {
+			return err
+		}
+		vm.inq = vm.inq[1:]
+
+	case print:
+		args, err := vm.args(instr, 1)
+		if err != nil {
+			return err
+		}
+		vm.out = append(vm.out, new(big.Int).Set(args[0]))
+
+	case jmpif, jmpnot:
+		args, err := vm.args(instr, 2)
+		if err != nil {
+			return err
+		}
+		if (args[0].Sign() != 0) == (instr.op == jmpif) {
+			if next, err = vm.addr(args[1], 0); err != nil {
+				return err
+			}
+		}
+
+	case adjrel:
+		args, err := vm.args(instr, 1)
+		if err != nil {
+			return err
+		}
+		by, err := vm.addr(args[0], 0)
+		if err != nil {
+			return err
+		}
+		vm.relbase += by
+
+	case halt:
+		vm.state = Halted
+		return nil
+	}
+	vm.pc = next
+	return nil
+}
+
+// Step executes a single instruction, unless the machine has halted.
+func (vm *BigMachine) Step() State {
+	if vm.state == Halted {
+		return vm.state
+	}
+	vm.state = Running
+	if err := vm.exec(); err != nil {
+		vm.err, vm.state = err, Halted
+	} else if vm.state != Blocked {
+		vm.steps++
+	}
+	return vm.state
+}
+
+// Run executes instructions until the machine halts or blocks on input.
+func (vm *BigMachine) Run() State {
+	for vm.Step() == Running {
+	}
+	return vm.state
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+func main() {
+	compress := flag.Bool("z", false, "compress the image with gzip")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: intimage [-z] program out")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var w io.WriteCloser = f
+	if *compress {
+		w = gzip.NewWriter(f)
+	}
+	if err := intcode.WriteImage(w, data); err != nil {
+		log.Fatal(err)
+	}
+	if *compress {
+		if err := w.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+type pokes map[int]int64
+
+func (p pokes) String() string {
+	return fmt.Sprint(map[int]int64(p))
+}
+
+func (p pokes) Set(s string) error {
+	toks := strings.SplitN(s, "=", 2)
+	if len(toks) != 2 {
+		return fmt.Errorf("want addr=value, got %q", s)
+	}
+	addr, err := strconv.Atoi(toks[0])
+	if err != nil {
+		return err
+	}
+	val, err := strconv.ParseInt(toks[1], 10, 64)
+	if err != nil {
+		return err
+	}
+	p[addr] = val
+	return nil
+}
+
+func main() {
+	set := pokes(make(map[int]int64))
+	flag.Var(set, "set", "overwrite memory before replaying, as addr=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: intreplay [-set addr=value]... program session")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for addr, val := range set {
+		if addr < 0 || addr >= len(data) {
+			log.Fatalf("bad address: %d", addr)
+		}
+		data[addr] = val
+	}
+	f, err := os.Open(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	s, err := intcode.ReadSession(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := intcode.Replay(data, s); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("ok: %d events replayed\n", len(s.Events()))
+}
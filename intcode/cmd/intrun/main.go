@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+func main() {
+	checked := flag.Bool("checked", false, "trap on signed overflow")
+	bigints := flag.Bool("big", false, "run on arbitrary-precision integers")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: intrun [-checked | -big] program [input]...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 || *checked && *bigints {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var in []*big.Int
+	for _, s := range flag.Args()[1:] {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok || !*bigints && !v.IsInt64() {
+			log.Fatalf("bad input: %s", s)
+		}
+		in = append(in, v)
+	}
+
+	var state intcode.State
+	if *bigints {
+		vm := intcode.NewBigMachine(data)
+		vm.Push(in...)
+		state = vm.Run()
+		for _, v := range vm.Outputs() {
+			fmt.Println(v)
+		}
+		err = vm.Err()
+	} else {
+		vm := intcode.NewMachine(data)
+		if *checked {
+			vm.CheckOverflow()
+		}
+		for _, v := range in {
+			vm.Push(v.Int64())
+		}
+		state = vm.Run()
+		for _, v := range vm.Outputs() {
+			fmt.Println(v)
+		}
+		err = vm.Err()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if state == intcode.Blocked {
+		log.Fatal("program is waiting for more input")
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/dhconnelly/advent-of-code-2019/viewer"
+	"github.com/gdamore/tcell"
+)
+
+// A flag that can be given more than once.
+type list []string
+
+func (l *list) String() string     { return strings.Join(*l, " ") }
+func (l *list) Set(s string) error { *l = append(*l, s); return nil }
+
+// A world is a preset for one of the puzzles' programs.
+type world struct {
+	desc  string
+	cfg   func() viewer.Config
+	pokes map[int64]int64
+}
+
+var arcadeStyle = tcell.StyleDefault.Background(tcell.ColorLightSlateGrey)
+
+var worlds = map[string]world{
+	"arcade": {
+		desc: "day 13's breakout game, played with the arrow keys",
+		cfg: func() viewer.Config {
+			return viewer.Config{
+				Decoder: viewer.Triples{Labels: map[geom.Pt2]string{{X: -1, Y: 0}: "score"}},
+				Tiles: map[int64]viewer.Tile{
+					0: {Rune: ' ', Style: arcadeStyle},
+					1: {Rune: '@', Style: arcadeStyle.Foreground(tcell.ColorBlack)},
+					2: {Rune: 'X', Style: arcadeStyle.Foreground(tcell.ColorBlue)},
+					3: {Rune: '-', Style: arcadeStyle.Foreground(tcell.ColorRed)},
+					4: {Rune: 'o', Style: arcadeStyle.Foreground(tcell.ColorYellow)},
+				},
+				Keys:  map[tcell.Key]int64{tcell.KeyLeft: -1, tcell.KeyRight: 1},
+				Idle:  []int64{0},
+				Speed: 2000,
+			}
+		},
+		pokes: map[int64]int64{0: 2}, // play for free
+	},
+	"droid": {
+		desc: "day 15's repair droid, exploring the whole area on its own",
+		cfg: func() viewer.Config {
+			return viewer.Config{
+				Decoder: &explorer{},
+				Tiles: map[int64]viewer.Tile{
+					droidWall:   {Rune: '#'},
+					droidOpen:   {Rune: '.'},
+					droidOxygen: {Rune: 'O', Style: tcell.StyleDefault.Foreground(tcell.ColorBlue)},
+					droidHere:   {Rune: 'D', Style: tcell.StyleDefault.Foreground(tcell.ColorRed)},
+				},
+				Speed: 2000,
+			}
+		},
+	},
+	"robot": {
+		desc: "day 11's hull painting robot, starting on a white panel",
+		cfg: func() viewer.Config {
+			return viewer.Config{
+				Decoder: newPainter(1),
+				Tiles:   map[int64]viewer.Tile{0: {Rune: '.'}, 1: {Rune: '#'}},
+				Speed:   500,
+			}
+		},
+	},
+	"camera": {
+		desc: "day 17's scaffold camera",
+		cfg: func() viewer.Config {
+			return viewer.Config{Decoder: &viewer.ASCII{}, Speed: 2000}
+		},
+	},
+}
+
+var keyNames = map[string]tcell.Key{
+	"left":  tcell.KeyLeft,
+	"right": tcell.KeyRight,
+	"up":    tcell.KeyUp,
+	"down":  tcell.KeyDown,
+	"enter": tcell.KeyEnter,
+	"tab":   tcell.KeyTab,
+}
+
+// Splits "k=v" and parses v as an integer.
+func assignment(s string) (string, int64, error) {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", 0, fmt.Errorf("bad assignment: %q", s)
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("bad value in %q: %w", s, err)
+	}
+	return k, n, nil
+}
+
+// Parses "value=rune" or "value=rune:color".
+func parseTile(s string) (int64, viewer.Tile, error) {
+	v, t, ok := strings.Cut(s, "=")
+	n, err := strconv.ParseInt(v, 10, 64)
+	if !ok || err != nil {
+		return 0, viewer.Tile{}, fmt.Errorf("bad tile: %q", s)
+	}
+	r, size := utf8.DecodeRuneInString(t)
+	if size == 0 {
+		return 0, viewer.Tile{}, fmt.Errorf("bad tile: %q", s)
+	}
+	tile := viewer.Tile{Rune: r}
+	if t = t[size:]; t != "" {
+		name := strings.TrimPrefix(t, ":")
+		c := tcell.GetColor(name)
+		if name == t || c == tcell.ColorDefault {
+			return 0, viewer.Tile{}, fmt.Errorf("bad color in tile: %q", s)
+		}
+		tile.Style = tcell.StyleDefault.Foreground(c)
+	}
+	return n, tile, nil
+}
+
+// Parses "name=value", where name is a key like "left" or a single
+// character.
+func parseKey(cfg *viewer.Config, s string) error {
+	name, n, err := assignment(s)
+	if err != nil {
+		return err
+	}
+	if k, ok := keyNames[name]; ok {
+		cfg.Keys[k] = n
+		return nil
+	}
+	if r, size := utf8.DecodeRuneInString(name); size > 0 && size == len(name) {
+		cfg.Runes[r] = n
+		return nil
+	}
+	return fmt.Errorf("unknown key: %q", name)
+}
+
+// Parses "x,y=name".
+func parseLabel(s string) (geom.Pt2, string, error) {
+	p, name, ok := strings.Cut(s, "=")
+	xs, ys, ok2 := strings.Cut(p, ",")
+	x, errx := strconv.Atoi(xs)
+	y, erry := strconv.Atoi(ys)
+	if !ok || !ok2 || errx != nil || erry != nil || name == "" {
+		return geom.Pt2{}, "", fmt.Errorf("bad label: %q", s)
+	}
+	return geom.Pt2{X: x, Y: y}, name, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: intview [flags] program")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "\nworlds:")
+	var names []string
+	for name := range worlds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, worlds[name].desc)
+	}
+}
+
+func main() {
+	var tiles, keys, labels, pokes list
+	preset := flag.String("world", "", "start from the settings for one of the worlds listed below")
+	asciiOut := flag.Bool("ascii", false, "decode output as ASCII frames instead of (x, y, value) triples")
+	flag.Var(&tiles, "tile", "draw a value as a rune in an optional color, like 2=X:blue (repeatable)")
+	flag.Var(&keys, "key", "send a value when a key is pressed, like left=-1 or a=97 (repeatable)")
+	flag.Var(&labels, "label", "show the value written at x,y in the status bar, like -1,0=score (repeatable)")
+	flag.Var(&pokes, "poke", "set an address before running, like 0=2 (repeatable)")
+	idle := flag.String("idle", "", "comma-separated input to send when no key has been pressed")
+	speed := flag.Int("speed", 0, "instructions to run per frame")
+	fps := flag.Int("fps", 0, "frames to draw per second")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := viewer.Config{}
+	poked := map[int64]int64{}
+	if *preset != "" {
+		w, ok := worlds[*preset]
+		if !ok {
+			log.Fatalf("unknown world: %s", *preset)
+		}
+		cfg = w.cfg()
+		for addr, v := range w.pokes {
+			poked[addr] = v
+		}
+	}
+	if cfg.Tiles == nil {
+		cfg.Tiles = make(map[int64]viewer.Tile)
+	}
+	if cfg.Keys == nil {
+		cfg.Keys = make(map[tcell.Key]int64)
+	}
+	if cfg.Runes == nil {
+		cfg.Runes = make(map[rune]int64)
+	}
+	switch {
+	case *asciiOut:
+		cfg.Decoder = &viewer.ASCII{}
+	case cfg.Decoder == nil:
+		cfg.Decoder = viewer.Triples{}
+	}
+	for _, s := range tiles {
+		v, t, err := parseTile(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.Tiles[v] = t
+	}
+	for _, s := range keys {
+		if err := parseKey(&cfg, s); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(labels) > 0 {
+		t, ok := cfg.Decoder.(viewer.Triples)
+		if !ok {
+			log.Fatal("labels only work with triples")
+		}
+		if t.Labels == nil {
+			t.Labels = make(map[geom.Pt2]string)
+		}
+		for _, s := range labels {
+			p, name, err := parseLabel(s)
+			if err != nil {
+				log.Fatal(err)
+			}
+			t.Labels[p] = name
+		}
+		cfg.Decoder = t
+	}
+	for _, s := range pokes {
+		addr, v, err := assignment(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		n, err := strconv.ParseInt(addr, 10, 64)
+		if err != nil || n < 0 {
+			log.Fatalf("bad address: %q", s)
+		}
+		poked[n] = v
+	}
+	if *idle != "" {
+		cfg.Idle = nil
+		for _, s := range strings.Split(*idle, ",") {
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				log.Fatalf("bad idle input: %q", s)
+			}
+			cfg.Idle = append(cfg.Idle, v)
+		}
+	}
+	if *speed > 0 {
+		cfg.Speed = *speed
+	}
+	if *fps > 0 {
+		cfg.FPS = *fps
+	}
+
+	data, err := intcode.ReadProgram(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for addr, v := range poked {
+		if addr >= int64(len(data)) {
+			log.Fatalf("can't poke address %d of %d", addr, len(data))
+		}
+		data[addr] = v
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = screen.Init(); err != nil {
+		log.Fatal(err)
+	}
+	vm := intcode.NewMachine(data)
+	v := viewer.New(screen, vm, cfg)
+	v.Run()
+	screen.Fini()
+	for _, name := range v.World().LabelNames() {
+		fmt.Printf("%s: %d\n", name, v.World().Labels[name])
+	}
+	if err := vm.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/viewer"
+)
+
+// painter drives day 11's hull painting robot: it reads the color of the
+// panel under the robot and outputs pairs of the color to paint it and
+// the way to turn, 0 for left and 1 for right, before moving forward.
+type painter struct {
+	start   int64 // color of the first panel
+	started bool
+	pos     geom.Pt2
+	dir     geom.Pt2 // in screen coordinates
+	painted map[geom.Pt2]bool
+}
+
+func newPainter(start int64) *painter {
+	return &painter{start: start, dir: geom.Pt2{X: 0, Y: -1}, painted: make(map[geom.Pt2]bool)}
+}
+
+func (p *painter) Decode(w *viewer.World, out []int64) []int64 {
+	for ; len(out) >= 2; out = out[2:] {
+		w.Cells.Set(p.pos, out[0])
+		p.painted[p.pos] = true
+		if out[1] == 0 {
+			p.dir = geom.Pt2{X: p.dir.Y, Y: -p.dir.X}
+		} else {
+			p.dir = geom.Pt2{X: -p.dir.Y, Y: p.dir.X}
+		}
+		p.pos = p.pos.Add(p.dir)
+	}
+	w.Labels["painted"] = int64(len(p.painted))
+	return out
+}
+
+func (p *painter) Input(w *viewer.World) []int64 {
+	if !p.started {
+		w.Cells.Set(p.pos, p.start)
+		p.started = true
+	}
+	return []int64{w.Cells.At(p.pos)}
+}
+
+// Cells drawn by the droid explorer: its statuses and the droid itself.
+const (
+	droidWall = iota
+	droidOpen
+	droidOxygen
+	droidHere
+)
+
+// Movement commands in screen coordinates.
+var droidMoves = []geom.Pt2{1: {X: 0, Y: -1}, 2: {X: 0, Y: 1}, 3: {X: -1, Y: 0}, 4: {X: 1, Y: 0}}
+
+func reverseMove(dir int64) int64 {
+	return [...]int64{1: 2, 2: 1, 3: 4, 4: 3}[dir]
+}
+
+// explorer drives day 15's repair droid around the whole area, depth
+// first: it tries each unknown neighbor in turn and backs up when there
+// are none left. The droid reads movement commands and outputs 0 if it
+// hit a wall, 1 if it moved, and 2 if it moved onto the oxygen system.
+type explorer struct {
+	started bool
+	pos     geom.Pt2
+	under   int64   // the cell the droid is drawn over
+	path    []int64 // moves from the start, for backing up
+	move    int64   // the last command sent
+	back    bool    // whether it was backing up
+}
+
+func (e *explorer) Decode(w *viewer.World, out []int64) []int64 {
+	for _, status := range out {
+		to := e.pos.Add(droidMoves[e.move])
+		if status == droidWall {
+			w.Cells.Set(to, droidWall)
+			continue
+		}
+		w.Cells.Set(e.pos, e.under)
+		if e.back {
+			e.under = w.Cells.At(to)
+		} else {
+			e.under = status
+			e.path = append(e.path, e.move)
+		}
+		if status == droidOxygen {
+			w.Labels["oxygen x"], w.Labels["oxygen y"] = int64(to.X), int64(to.Y)
+		}
+		e.pos = to
+		w.Cells.Set(e.pos, droidHere)
+	}
+	return nil
+}
+
+func (e *explorer) Input(w *viewer.World) []int64 {
+	if !e.started {
+		e.under = droidOpen
+		w.Cells.Set(e.pos, droidHere)
+		e.started = true
+	}
+	for dir := int64(1); dir <= 4; dir++ {
+		if _, ok := w.Cells.Get(e.pos.Add(droidMoves[dir])); !ok {
+			e.move, e.back = dir, false
+			return []int64{dir}
+		}
+	}
+	if len(e.path) == 0 {
+		return nil // explored everything
+	}
+	e.move, e.back = reverseMove(e.path[len(e.path)-1]), true
+	e.path = e.path[:len(e.path)-1]
+	return []int64{e.move}
+}
@@ -0,0 +1,176 @@
+package intcode
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// A conformance case from testdata/conformance.json. Memory maps
+// addresses to their expected values after the program stops, and Error
+// is empty if the program should halt normally, "bad opcode" or "bad
+// mode" if it hits an instruction that can't be executed, and "no
+// input" if it tries to read after the input runs out.
+type conformanceCase struct {
+	Name    string
+	Program []int64
+	Input   []int64
+	Output  []int64
+	Memory  map[int64]int64
+	Error   string
+}
+
+func loadConformance(t *testing.T) []conformanceCase {
+	b, err := os.ReadFile("testdata/conformance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []conformanceCase
+	if err := json.Unmarshal(b, &cases); err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+func errorKind(state State, err error) string {
+	var ie *InstructionError
+	switch {
+	case state == Blocked:
+		return "no input"
+	case errors.As(err, &ie):
+		return ie.Err.Error()
+	case err != nil:
+		return err.Error()
+	}
+	return ""
+}
+
+func sameOutputs(got, want []int64) bool {
+	return len(got) == 0 && len(want) == 0 || reflect.DeepEqual(got, want)
+}
+
+func TestConformance(t *testing.T) {
+	for _, tc := range loadConformance(t) {
+		vm := NewMachine(tc.Program)
+		vm.Push(tc.Input...)
+		state := vm.Run()
+		if got := errorKind(state, vm.Err()); got != tc.Error {
+			t.Errorf("%s: error = %q (%v), want %q", tc.Name, got, vm.Err(), tc.Error)
+		}
+		if got := vm.Outputs(); !sameOutputs(got, tc.Output) {
+			t.Errorf("%s: outputs = %v, want %v", tc.Name, got, tc.Output)
+		}
+		for addr, want := range tc.Memory {
+			if got := vm.Mem(addr); got != want {
+				t.Errorf("%s: mem[%d] = %d, want %d", tc.Name, addr, got, want)
+			}
+		}
+	}
+}
+
+func TestConformanceBig(t *testing.T) {
+	for _, tc := range loadConformance(t) {
+		vm := NewBigMachine(tc.Program)
+		vm.PushInt64(tc.Input...)
+		state := vm.Run()
+		if got := errorKind(state, vm.Err()); got != tc.Error {
+			t.Errorf("%s: error = %q (%v), want %q", tc.Name, got, vm.Err(), tc.Error)
+		}
+		var got []int64
+		for _, v := range vm.Outputs() {
+			got = append(got, v.Int64())
+		}
+		if !sameOutputs(got, tc.Output) {
+			t.Errorf("%s: outputs = %v, want %v", tc.Name, got, tc.Output)
+		}
+		for addr, want := range tc.Memory {
+			if got := vm.Mem(addr); got.Cmp(big.NewInt(want)) != 0 {
+				t.Errorf("%s: mem[%d] = %s, want %d", tc.Name, addr, got, want)
+			}
+		}
+	}
+}
+
+// The other implementations' conformance runners, relative to this
+// directory. Each takes a program file as its only argument, reads input
+// values from stdin, writes output values to stdout one per line, and
+// exits with a nonzero status if the program fails. Only the outputs and
+// whether the program failed are checked.
+var runners = []struct {
+	name  string
+	paths []string
+}{
+	{"c", []string{"../c/target/intrun"}},
+	{"rust", []string{"../rs/intrun/target/release/intrun", "../rs/intrun/target/debug/intrun"}},
+	{"ocaml", []string{"../ocaml/intrun/intrun"}},
+}
+
+func formatInts(xs []int64, sep string) string {
+	toks := make([]string, len(xs))
+	for i, x := range xs {
+		toks[i] = strconv.FormatInt(x, 10)
+	}
+	return strings.Join(toks, sep)
+}
+
+func runExternal(path, prog string, input []int64) ([]int64, error) {
+	cmd := exec.Command(path, prog)
+	cmd.Stdin = strings.NewReader(formatInts(input, "\n") + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	var out []int64
+	for _, tok := range strings.Fields(string(stdout)) {
+		v, perr := strconv.ParseInt(tok, 10, 64)
+		if perr != nil {
+			return out, fmt.Errorf("bad output %q", tok)
+		}
+		out = append(out, v)
+	}
+	if err != nil {
+		return out, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func TestConformanceExternal(t *testing.T) {
+	cases := loadConformance(t)
+	dir := t.TempDir()
+	for _, r := range runners {
+		r := r
+		t.Run(r.name, func(t *testing.T) {
+			var path string
+			for _, p := range r.paths {
+				if _, err := os.Stat(p); err == nil {
+					path = p
+					break
+				}
+			}
+			if path == "" {
+				t.Skipf("no runner built at %s", strings.Join(r.paths, " or "))
+			}
+			for i, tc := range cases {
+				prog := filepath.Join(dir, fmt.Sprintf("%d.txt", i))
+				if err := os.WriteFile(prog, []byte(formatInts(tc.Program, ",")+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				got, err := runExternal(path, prog, tc.Input)
+				if (err != nil) != (tc.Error != "") {
+					t.Errorf("%s: err = %v, want %q", tc.Name, err, tc.Error)
+				}
+				if !sameOutputs(got, tc.Output) {
+					t.Errorf("%s: outputs = %v, want %v", tc.Name, got, tc.Output)
+				}
+			}
+		})
+	}
+}
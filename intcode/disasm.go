@@ -14,6 +14,7 @@ const (
 
 type Instruction struct {
 	Opcode Opcode
+	Name   string
 	Modes  []Mode
 }
 
@@ -37,7 +38,7 @@ func joinInts(ints []int64) string {
 func (l Line) String() string {
 	switch l.Which {
 	case Instr:
-		s := fmt.Sprintf("[%4d] %8s    ", l.Offset, l.Instr.Opcode)
+		s := fmt.Sprintf("[%4d] %8s    ", l.Offset, l.Instr.Name)
 		args := make([]string, len(l.Instr.Modes))
 		for i, md := range l.Instr.Modes {
 			args[i] = fmt.Sprintf("%8s", fmt.Sprintf("%s(%d)", md, l.Data[i+1]))
@@ -48,18 +49,27 @@ func (l Line) String() string {
 	}
 }
 
+// Disassemble disassembles a program that uses only the built-in
+// instructions.
 func Disassemble(data []int64) []Line {
+	return builtinOps.Disassemble(data)
+}
+
+// Disassemble disassembles a program that uses the instructions in s.
+func (s *OpSet) Disassemble(data []int64) []Line {
 	var lines []Line
-	var line Line
 	for i := 0; i < len(data); {
-		line.Offset = i
-		instr := parseInstruction(data[i])
-		if !instr.op.isValid() {
+		line := Line{Offset: i}
+		instr := s.parse(data[i])
+		_, valid := s.lookup(instr.op)
+		// an instruction that runs past the end is shown as data
+		if !valid || i+int(instr.arity) >= len(data) {
 			line.Which = RawData
 			line.Width = 1
 		} else {
 			line.Which = Instr
 			line.Instr.Opcode = instr.op
+			line.Instr.Name = s.Name(instr.op)
 			line.Instr.Modes = instr.modes
 			line.Width = int(instr.arity) + 1
 		}
@@ -0,0 +1,175 @@
+package intcode
+
+import (
+	"fmt"
+)
+
+// Op describes an instruction to add to an instruction set with NewOpSet.
+type Op struct {
+	Code  Opcode
+	Name  string
+	Arity int
+
+	// Writes lists the operands, numbered from zero, that the
+	// instruction writes to rather than reads from.
+	Writes []int
+
+	Handler func(c *Call)
+}
+
+// Call is an instruction being executed by an extension handler. Unless
+// the handler jumps, halts or blocks, the machine moves on to the next
+// instruction when it returns. If the handler uses an operand that the
+// instruction doesn't have, or sets one that isn't listed in Writes, the
+// machine stops with an *InstructionError wrapping ErrBadOperand when
+// the handler returns.
+type Call struct {
+	m     *machine
+	instr instruction
+	info  opInfo
+
+	jumped, halted, blocked, failed bool
+}
+
+func (c *Call) PC() int64 {
+	return c.m.pc
+}
+
+func (c *Call) RelBase() int64 {
+	return c.m.relbase
+}
+
+// Records a misused operand. Only the first one is kept.
+func (c *Call) fail() {
+	if !c.failed {
+		c.failed = true
+		c.m.err = &InstructionError{c.m.pc, c.m.data[c.m.pc], ErrBadOperand}
+	}
+}
+
+func (c *Call) operand(i int) (int64, bool) {
+	if i < 0 || int64(i) >= c.instr.arity {
+		c.fail()
+		return 0, false
+	}
+	return c.m.pc + int64(i) + 1, true
+}
+
+// Arg returns the value of the i-th operand according to its mode, or 0
+// if there is no such operand.
+func (c *Call) Arg(i int) int64 {
+	addr, ok := c.operand(i)
+	if !ok {
+		return 0
+	}
+	return c.m.get(addr, c.instr.modes[i])
+}
+
+// Set writes to the i-th operand, which must be listed in Writes.
+func (c *Call) Set(i int, val int64) {
+	addr, ok := c.operand(i)
+	if !ok {
+		return
+	}
+	if !writes(c.info, i) {
+		c.fail()
+		return
+	}
+	c.m.set(addr, val, c.instr.modes[i])
+}
+
+func (c *Call) Jump(addr int64) {
+	c.m.pc = addr
+	c.jumped = true
+}
+
+func (c *Call) AdjustRelBase(by int64) {
+	c.m.relbase += by
+}
+
+// Read takes the next input value. If none is available, the handler
+// must return without side effects; the machine then blocks and retries
+// the instruction once there is input.
+func (c *Call) Read() (int64, bool) {
+	v, ok := c.m.read()
+	if !ok {
+		c.blocked = true
+	}
+	return v, ok
+}
+
+func (c *Call) Write(v int64) {
+	c.m.write(v)
+}
+
+func (c *Call) Halt() {
+	c.halted = true
+}
+
+func (op Op) handler(info opInfo) handler {
+	return func(m *machine, instr instruction) bool {
+		c := &Call{m: m, instr: instr, info: info}
+		op.Handler(c)
+		switch {
+		case c.failed:
+			m.state = Halted
+			return false
+		case c.blocked:
+			m.state = Blocked
+			return false
+		case c.halted:
+			m.state = Halted
+			return false
+		case !c.jumped:
+			m.pc += instr.arity + 1
+		}
+		return true
+	}
+}
+
+// NewOpSet returns an instruction set with the built-in instructions and
+// the given extensions, for making machines with OpSet.NewMachine and
+// OpSet.Run and disassembling their programs with OpSet.Disassemble.
+// Opcodes must be between 1 and 99 and may not replace built-in
+// instructions or each other.
+func NewOpSet(extra ...Op) (*OpSet, error) {
+	s := &OpSet{ops: make(map[Opcode]opInfo, len(builtinOps.ops)+len(extra))}
+	for code, info := range builtinOps.ops {
+		s.ops[code] = info
+	}
+	for _, op := range extra {
+		if err := s.add(op); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *OpSet) add(op Op) error {
+	switch {
+	case op.Code < 1 || op.Code > 99:
+		return fmt.Errorf("bad opcode: %d", op.Code)
+	case op.Name == "":
+		return fmt.Errorf("missing name for opcode %d", op.Code)
+	case op.Arity < 0:
+		return fmt.Errorf("bad arity for %s: %d", op.Name, op.Arity)
+	case op.Handler == nil:
+		return fmt.Errorf("missing handler for %s", op.Name)
+	}
+	for _, w := range op.Writes {
+		if w < 0 || w >= op.Arity {
+			return fmt.Errorf("bad write operand for %s: %d", op.Name, w)
+		}
+	}
+	if info, ok := s.ops[op.Code]; ok {
+		return fmt.Errorf("opcode %d already used by %s", op.Code, info.name)
+	}
+	info := opInfo{
+		name:   op.Name,
+		arity:  int64(op.Arity),
+		writes: append([]int(nil), op.Writes...),
+	}
+	info.h = op.handler(info)
+	s.ops[op.Code] = info
+	return nil
+}
@@ -0,0 +1,132 @@
+package intcode
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testOps = []Op{
+	{Code: 20, Name: "sqr", Arity: 2, Writes: []int{1}, Handler: func(c *Call) {
+		v := c.Arg(0)
+		c.Set(1, v*v)
+	}},
+	{Code: 21, Name: "echo", Handler: func(c *Call) {
+		if v, ok := c.Read(); ok {
+			c.Write(v)
+		}
+	}},
+	{Code: 22, Name: "hcf", Handler: func(c *Call) {
+		c.Halt()
+	}},
+}
+
+func newTestOpSet(t *testing.T) *OpSet {
+	t.Helper()
+	s, err := NewOpSet(testOps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestExtendedOps(t *testing.T) {
+	// sqr 7 into [10]; print [10]; echo; echo; hcf
+	data := []int64{120, 7, 10, 4, 10, 21, 21, 22, 99, 0, 0}
+	vm := newTestOpSet(t).NewMachine(data)
+	if state := vm.Run(); state != Blocked {
+		t.Fatalf("state = %s, want %s", state, Blocked)
+	}
+	vm.Push(3, 4)
+	if state := vm.Run(); state != Halted {
+		t.Fatalf("state = %s, want %s", state, Halted)
+	}
+	if got, want := vm.Outputs(), []int64{49, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outputs = %v, want %v", got, want)
+	}
+	if steps := vm.Steps(); steps != 5 {
+		t.Errorf("steps = %d, want 5", steps)
+	}
+}
+
+func TestBuiltinOpsUnchanged(t *testing.T) {
+	newTestOpSet(t)
+	vm := NewMachine([]int64{120, 7, 10, 99})
+	vm.Run()
+	if !errors.Is(vm.Err(), ErrBadOpcode) {
+		t.Errorf("extension ran on a plain machine: err = %v", vm.Err())
+	}
+	if lines := Disassemble([]int64{21}); lines[0].Which != RawData {
+		t.Errorf("plain disassembly knows echo: %v", lines[0])
+	}
+}
+
+func TestExtendedDisassembly(t *testing.T) {
+	lines := newTestOpSet(t).Disassemble([]int64{120, 7, 10, 21, 22})
+	var got []string
+	for _, line := range lines {
+		got = append(got, line.Instr.Name)
+	}
+	if want := []string{"sqr", "echo", "hcf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("disassembled %v, want %v", got, want)
+	}
+	if lines[0].Width != 3 {
+		t.Errorf("sqr width = %d, want 3", lines[0].Width)
+	}
+}
+
+func TestExtendedTrace(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	in := make(chan int64, 1)
+	in <- 5
+	for range newTestOpSet(t).Run([]int64{21, 22}, in, true) {
+	}
+	if got := buf.String(); !strings.Contains(got, "echo") || !strings.Contains(got, "hcf") {
+		t.Errorf("trace doesn't name the extensions:\n%s", got)
+	}
+}
+
+func TestNewOpSetErrors(t *testing.T) {
+	nop := func(c *Call) {}
+	if _, err := NewOpSet(testOps[0], Op{Code: 20, Name: "sqr2", Arity: 2, Handler: nop}); err == nil {
+		t.Error("NewOpSet accepted the same opcode twice")
+	}
+	for _, op := range []Op{
+		{Code: 1, Name: "plus", Arity: 3, Handler: nop},
+		{Code: 100, Name: "big", Handler: nop},
+		{Code: 30, Arity: 1, Handler: nop},
+		{Code: 30, Name: "nohandler"},
+		{Code: 30, Name: "badwrite", Arity: 1, Writes: []int{1}, Handler: nop},
+	} {
+		if _, err := NewOpSet(op); err == nil {
+			t.Errorf("NewOpSet(%+v) succeeded", op)
+		}
+	}
+}
+
+func TestMisusedOperands(t *testing.T) {
+	for _, h := range []func(c *Call){
+		func(c *Call) { c.Write(c.Arg(1)) }, // no such operand
+		func(c *Call) { c.Set(0, 7) },       // not writable
+	} {
+		s, err := NewOpSet(Op{Code: 30, Name: "oops", Arity: 1, Handler: h})
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm := s.NewMachine([]int64{104, 1, 30, 5, 99, 0})
+		vm.Run()
+		var ierr *InstructionError
+		if err := vm.Err(); !errors.As(err, &ierr) || !errors.Is(err, ErrBadOperand) || ierr.PC != 2 {
+			t.Errorf("Err() = %v, want a bad operand at pos 2", err)
+		}
+		if vm.State() != Halted || vm.PC() != 2 || vm.Steps() != 1 {
+			t.Errorf("state %s at pc %d after %d steps", vm.State(), vm.PC(), vm.Steps())
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package intcode
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Decodes fuzzer bytes as a sequence of varints, so that small values,
+// and so real opcodes, are common.
+func varints(b []byte) []int64 {
+	var xs []int64
+	for len(b) > 0 {
+		x, n := binary.Varint(b)
+		if n <= 0 {
+			break
+		}
+		xs = append(xs, x)
+		b = b[n:]
+	}
+	return xs
+}
+
+func appendVarints(b []byte, xs ...int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, x := range xs {
+		b = append(b, buf[:binary.PutVarint(buf, x)]...)
+	}
+	return b
+}
+
+func FuzzParseInstruction(f *testing.F) {
+	for _, i := range []int64{1, 1002, 21101, 203, 99, 0, -1, 30004} {
+		f.Add(i)
+	}
+	f.Fuzz(func(t *testing.T, i int64) {
+		instr := parseInstruction(i)
+		if int64(len(instr.modes)) != instr.arity {
+			t.Fatalf("%d: %d modes for arity %d", i, len(instr.modes), instr.arity)
+		}
+		info, ok := lookup(instr.op)
+		if !ok {
+			if instr.arity != 0 {
+				t.Fatalf("%d: invalid op %d has arity %d", i, instr.op, instr.arity)
+			}
+			return
+		}
+		if instr.op != Opcode(i%100) || instr.arity != info.arity {
+			t.Fatalf("%d: got %v, want op %d with arity %d", i, instr, i%100, info.arity)
+		}
+		for j, md := range instr.modes {
+			want := i / 100
+			for k := 0; k < j; k++ {
+				want /= 10
+			}
+			if md != Mode(want%10) {
+				t.Fatalf("%d: mode %d = %d, want %d", i, j, md, want%10)
+			}
+		}
+	})
+}
+
+func TestDisassembleTruncated(t *testing.T) {
+	got := Disassemble([]int64{104, 7, 1001, 5})
+	want := []Line{
+		{Offset: 0, Width: 2, Which: Instr, Data: []int64{104, 7}, Instr: Instruction{print, "print", []Mode{imm}}},
+		{Offset: 2, Width: 1, Which: RawData, Data: []int64{1001}},
+		{Offset: 3, Width: 1, Which: RawData, Data: []int64{5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Disassemble = %v, want %v", got, want)
+	}
+}
+
+func FuzzDisassemble(f *testing.F) {
+	f.Add(appendVarints(nil, 1, 0, 0, 0, 99))
+	f.Add(appendVarints(nil, 109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99))
+	f.Add(appendVarints(nil, 1002, 4))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		data := varints(b)
+		offset := 0
+		for _, line := range Disassemble(data) {
+			if line.Offset != offset || line.Width != len(line.Data) || line.Width < 1 {
+				t.Fatalf("%v: bad line at %d: %+v", data, offset, line)
+			}
+			_ = line.String()
+			offset += line.Width
+		}
+		if offset != len(data) {
+			t.Fatalf("%v: disassembled %d values", data, offset)
+		}
+	})
+}
+
+func FuzzReadProgram(f *testing.F) {
+	f.Add("1,0,0,0,99\n")
+	f.Add("104,-1125899906842624,99")
+	f.Add("")
+	f.Add("1,,2")
+	f.Fuzz(func(t *testing.T, s string) {
+		path := filepath.Join(t.TempDir(), "prog.txt")
+		if err := os.WriteFile(path, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ReadProgram(path)
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), path+": ") {
+				t.Fatalf("%q: error %q doesn't name the file", s, err)
+			}
+			return
+		}
+		// whatever was read must survive a round trip
+		if err := os.WriteFile(path, []byte(formatInts(data, ",")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		again, err := ReadProgram(path)
+		if err != nil {
+			t.Fatalf("%q: rereading %v: %s", s, data, err)
+		}
+		if !sameOutputs(data, again) {
+			t.Fatalf("%q: read %v, then %v", s, data, again)
+		}
+	})
+}
+
+// Random programs may loop forever, so they only get a fixed number of
+// steps.
+const fuzzBudget = 10000
+
+func FuzzRun(f *testing.F) {
+	f.Add(appendVarints(nil, 3, 0, 4, 0, 99), appendVarints(nil, 42))
+	f.Add(appendVarints(nil, 109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99), []byte(nil))
+	f.Add(appendVarints(nil, 1105, 1, 0), []byte(nil))
+	f.Add(appendVarints(nil, 1102, 1<<40, 1<<40, 7, 4, 7, 99, 0), []byte(nil))
+	f.Fuzz(func(t *testing.T, code, input []byte) {
+		vm := NewMachine(varints(code))
+		vm.Push(varints(input)...)
+		n := 0
+		for ; n < fuzzBudget && vm.Step() == Running; n++ {
+		}
+		if vm.Steps() > int64(n)+1 {
+			t.Fatalf("%d steps counted in %d calls", vm.Steps(), n+1)
+		}
+		if vm.Err() != nil && vm.State() != Halted {
+			t.Fatalf("error %q but state %s", vm.Err(), vm.State())
+		}
+	})
+}
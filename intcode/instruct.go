@@ -29,9 +29,14 @@ type instruction struct {
 }
 
 func parseInstruction(i int64) instruction {
+	return builtinOps.parse(i)
+}
+
+func (s *OpSet) parse(i int64) instruction {
 	var in instruction
 	in.op = Opcode(i % 100)
-	in.arity = opcodeToArity[in.op]
+	info, _ := s.lookup(in.op)
+	in.arity = info.arity
 	for i /= 100; int64(len(in.modes)) < in.arity; i /= 10 {
 		in.modes = append(in.modes, Mode(i%10))
 	}
@@ -1,45 +1,38 @@
 package intcode
 
-import (
-	"bufio"
-	"fmt"
-	"io"
-	"os"
-)
-
 func RunProgram(data []int64, in <-chan int64) <-chan int64 {
 	return Run(data, in, false)
 }
 
+// Run runs a program in its own goroutine, reading input from in and
+// writing output to the returned channel, which is closed when the
+// program halts. If the program hits an instruction it can't execute,
+// Run exits the process; use Start to handle the error instead.
 func Run(data []int64, in <-chan int64, dbg bool) <-chan int64 {
+	return builtinOps.Run(data, in, dbg)
+}
+
+// Run is like the package's Run, but runs the instructions in s. When
+// dbg is set, the trace shows extensions by name.
+func (s *OpSet) Run(data []int64, in <-chan int64, dbg bool) <-chan int64 {
 	out := make(chan int64)
-	m := newMachine(data, in, out, dbg)
-	go m.run()
+	m := newChanMachine(data, in, out, dbg)
+	m.ops = s
+	go func() {
+		m.run()
+		close(out)
+	}()
 	return out
 }
 
-func ReadProgram(path string) ([]int64, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	var data []int64
-	for r := bufio.NewReader(f); ; {
-		tok, err := r.ReadString(',')
-		if err != nil && err != io.EOF {
-			return nil, fmt.Errorf("bad int in %s: %w", path, err)
-		}
-		if len(tok) > 0 {
-			var i int64
-			if _, err := fmt.Sscanf(tok, "%d", &i); err != nil {
-				return nil, fmt.Errorf("bad int in %s: %w", path, err)
-			}
-			data = append(data, i)
-		}
-		if err == io.EOF {
-			break
-		}
-	}
-	return data, nil
+// Start is like RunProgram, but if the program hits an instruction it
+// can't execute, out is closed and wait returns an *InstructionError.
+// wait blocks until the program stops, and returns nil if it halted
+// normally. Once the program stops, values sent on in are discarded, so
+// a caller that's sending input when the program fails sees out closed
+// on its next receive instead of blocking forever.
+func Start(data []int64, in <-chan int64) (out <-chan int64, wait func() error) {
+	ch := make(chan int64)
+	m := newChanMachine(data, in, ch, false)
+	return ch, m.start(in, ch)
 }
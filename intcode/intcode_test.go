@@ -0,0 +1,43 @@
+package intcode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	in := make(chan int64, 1)
+	in <- 3
+	out, wait := Start(countdown, in)
+	var got []int64
+	for v := range out {
+		got = append(got, v)
+	}
+	if want := []int64{3, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outputs = %v, want %v", got, want)
+	}
+	if err := wait(); err != nil {
+		t.Errorf("wait() = %v", err)
+	}
+}
+
+func TestStartBadInstruction(t *testing.T) {
+	// print 7; read into [6]; bad opcode
+	in := make(chan int64)
+	out, wait := Start([]int64{104, 7, 3, 6, 42, 99, 0}, in)
+	if v := <-out; v != 7 {
+		t.Fatalf("first output = %d, want 7", v)
+	}
+	in <- 1
+	// the program has failed, so this would block without the drain
+	in <- 2
+	if _, ok := <-out; ok {
+		t.Error("out wasn't closed")
+	}
+	var ierr *InstructionError
+	if err := wait(); !errors.As(err, &ierr) || !errors.Is(err, ErrBadOpcode) || ierr.PC != 4 {
+		t.Errorf("wait() = %v, want a bad opcode at pos 4", err)
+	}
+	close(in)
+}
@@ -0,0 +1,58 @@
+package intcodetest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// A Runner runs prog with the given input and returns its output.
+type Runner func(prog, input []int64) ([]int64, error)
+
+// Chans adapts an interpreter that runs a program over channels of ints,
+// returning when it halts, to a Runner. The input channel is closed
+// after the input, so reading past it doesn't block.
+func Chans(run func(data []int, in <-chan int, out chan<- int) error) Runner {
+	return func(prog, input []int64) ([]int64, error) {
+		data := make([]int, len(prog))
+		for i, v := range prog {
+			data[i] = int(v)
+		}
+		in, out := make(chan int, len(input)), make(chan int)
+		for _, v := range input {
+			in <- int(v)
+		}
+		close(in)
+		var err error
+		go func() {
+			err = run(data, in, out)
+			close(out)
+		}()
+		var outputs []int64
+		for v := range out {
+			outputs = append(outputs, int64(v))
+		}
+		return outputs, err
+	}
+}
+
+// FuzzAgainst fuzzes run with the programs made by Program, checking that
+// it gives the same output as the intcode package.
+func FuzzAgainst(f *testing.F, run Runner) {
+	f.Add([]byte{})
+	f.Add([]byte("\x03\x07\x00\x01\x04\x05\x04\x00"))
+	f.Add([]byte("\x05\x04\x00\x02\x03\x02\x11\x01\x40\x02\x00\x07\x81\x00\x06\x01\x04\x03\x00"))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		prog, input := Program(b)
+		want, err := Outputs(prog, input)
+		if err != nil {
+			t.Fatalf("%v: %s", prog, err)
+		}
+		got, err := run(prog, input)
+		if err != nil {
+			t.Fatalf("%v with input %v: %s", prog, input, err)
+		}
+		if !(len(got) == 0 && len(want) == 0 || reflect.DeepEqual(got, want)) {
+			t.Fatalf("%v with input %v: got %v, want %v", prog, input, got, want)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Package intcodetest generates intcode programs for testing other
+// interpreters against the intcode package.
+package intcodetest
+
+import (
+	"fmt"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+// Cells at the end of every program that instructions write to.
+const dataLen = 8
+
+type source []byte
+
+func (s *source) next() int {
+	if len(*s) == 0 {
+		return 0
+	}
+	b := (*s)[0]
+	*s = (*s)[1:]
+	return int(b)
+}
+
+// An operand before addresses are known: a jump target is the index of
+// an instruction, a write is the index of a data cell, and anything else
+// is a value in immediate mode or an address in position mode.
+type operand struct {
+	imm    bool
+	target bool
+	write  bool
+	v      int
+}
+
+type instr struct {
+	op   int64
+	args []operand
+}
+
+// The day 5 instruction set.
+var arities = []struct {
+	op    int64
+	arity int
+}{
+	{1, 3}, {2, 3}, {3, 1}, {4, 1}, {5, 2}, {6, 2}, {7, 3}, {8, 3},
+}
+
+// Program turns arbitrary bytes into a valid program using only the day 5
+// instruction set: add, mul, read, print, jumps and comparisons, in
+// position and immediate modes. Writes only touch the data cells after
+// the final halt, every address is inside the program, and jumps only go
+// forward, so the program always halts without reading or writing out of
+// bounds. It also returns enough input for every read.
+func Program(b []byte) (prog, input []int64) {
+	src := source(b)
+	var instrs []instr
+	for len(src) > 0 && len(instrs) < 64 {
+		a := arities[src.next()%len(arities)]
+		in := instr{op: a.op}
+		for i := 0; i < a.arity; i++ {
+			arg := operand{v: src.next()}
+			switch {
+			case a.op == 3 || i == 2:
+				arg.write = true
+			case (a.op == 5 || a.op == 6) && i == 1:
+				arg.target, arg.imm = true, true
+			default:
+				arg.imm = src.next()%2 == 1
+			}
+			in.args = append(in.args, arg)
+		}
+		if a.op == 3 {
+			input = append(input, int64(int8(src.next())))
+		}
+		instrs = append(instrs, in)
+	}
+
+	// lay out the code to find where each instruction starts
+	offsets := make([]int64, len(instrs)+1)
+	for i, in := range instrs {
+		offsets[i+1] = offsets[i] + int64(len(in.args)) + 1
+	}
+	dataStart := offsets[len(instrs)] + 1
+	size := int(dataStart) + dataLen
+
+	for i, in := range instrs {
+		code, scale := in.op, int64(100)
+		var args []int64
+		for _, arg := range in.args {
+			var v int64
+			switch {
+			case arg.target:
+				v = offsets[i+1+arg.v%(len(instrs)-i)]
+			case arg.write:
+				v = dataStart + int64(arg.v%dataLen)
+			case arg.imm:
+				v = int64(int8(arg.v))
+			default:
+				v = int64(arg.v % size)
+			}
+			if arg.imm {
+				code += scale
+			}
+			scale *= 10
+			args = append(args, v)
+		}
+		prog = append(prog, code)
+		prog = append(prog, args...)
+	}
+	prog = append(prog, 99)
+	for i := 0; i < dataLen; i++ {
+		prog = append(prog, int64(int8(src.next())))
+	}
+	return prog, input
+}
+
+// Outputs runs prog on an intcode machine with the given input and
+// returns its output, or an error if it doesn't halt cleanly.
+func Outputs(prog, input []int64) ([]int64, error) {
+	vm := intcode.NewMachine(prog)
+	vm.Push(input...)
+	if state := vm.Run(); state != intcode.Halted {
+		return nil, fmt.Errorf("machine %s", state)
+	}
+	if err := vm.Err(); err != nil {
+		return nil, err
+	}
+	return vm.Outputs(), nil
+}
@@ -1,26 +1,51 @@
 package intcode
 
 import (
+	"errors"
 	"fmt"
 	"log"
 )
 
+type State int
+
+const (
+	Running State = iota
+	Blocked       // waiting for input
+	Halted
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Blocked:
+		return "blocked"
+	case Halted:
+		return "halted"
+	}
+	return ""
+}
+
 type machine struct {
 	pc      int64
 	relbase int64
 	data    map[int64]int64
-	in      <-chan int64
-	out     chan<- int64
+	ops     *OpSet
+	state   State
+	steps   int64
+	in      func() (int64, bool)
+	out     func(int64)
 	dbg     bool
+	checked bool  // trap on overflow
+	err     error // why the machine trapped
 }
 
-func newMachine(data []int64, in <-chan int64, out chan<- int64, dbg bool) *machine {
+func newMachine(data []int64, dbg bool) *machine {
 	m := &machine{
 		pc:      0,
 		relbase: 0,
 		data:    make(map[int64]int64),
-		in:      in,
-		out:     out,
+		ops:     builtinOps,
 		dbg:     dbg,
 	}
 	for i, v := range data {
@@ -29,6 +54,13 @@ func newMachine(data []int64, in <-chan int64, out chan<- int64, dbg bool) *mach
 	return m
 }
 
+func newChanMachine(data []int64, in <-chan int64, out chan<- int64, dbg bool) *machine {
+	m := newMachine(data, dbg)
+	m.in = func() (int64, bool) { return <-in, true }
+	m.out = func(v int64) { out <- v }
+	return m
+}
+
 // Retrieves a value according to the specified mode.
 //
 // * In immediate mode, returns the value stored at the given address.
@@ -84,40 +116,121 @@ func (m *machine) set(addr, val int64, md Mode) {
 	}
 }
 
-func (m *machine) read() int64 {
-	v := <-m.in
-	if m.dbg {
+func (m *machine) read() (int64, bool) {
+	v, ok := m.in()
+	if ok && m.dbg {
 		log.Println("read value:", v) // uncomment for debugging (TODO: use a flag)
 	}
-	return v
+	return v, ok
 }
 
 func (m *machine) write(v int64) {
-	m.out <- v
+	m.out(v)
 	if m.dbg {
 		log.Println("wrote value:", v) // uncomment for debugging (TODO: use a flag)
 	}
 }
 
 func (m *machine) log(instr instruction) {
-	line := fmt.Sprintf("[%3d] %s", m.pc, instr.op)
+	line := fmt.Sprintf("[%3d] %s", m.pc, m.ops.Name(instr.op))
 	for i := int64(0); i < instr.arity; i++ {
 		line += fmt.Sprintf(" %s(%d)", instr.modes[i], m.data[m.pc+i+1])
 	}
 	log.Println(line)
 }
 
-func (m *machine) run() {
-	for ok := true; ok; {
-		instr := parseInstruction(m.data[m.pc])
-		if m.dbg {
-			m.log(instr)
+var (
+	ErrBadOpcode  = errors.New("bad opcode")
+	ErrBadMode    = errors.New("bad mode")
+	ErrBadOperand = errors.New("bad operand")
+)
+
+// InstructionError describes an instruction that the machine couldn't
+// execute, either because its opcode is unknown, because one of its
+// operands has an unknown mode or is written to in immediate mode, or
+// because an extension's handler misused its operands.
+type InstructionError struct {
+	PC    int64
+	Instr int64
+	Err   error // ErrBadOpcode, ErrBadMode or ErrBadOperand
+}
+
+func (e *InstructionError) Error() string {
+	return fmt.Sprintf("%s at pos %d: %d", e.Err, e.PC, e.Instr)
+}
+
+func (e *InstructionError) Unwrap() error {
+	return e.Err
+}
+
+func writes(info opInfo, i int) bool {
+	for _, w := range info.writes {
+		if w == i {
+			return true
 		}
-		if h, present := handlers[instr.op]; present {
-			ok = h(m, instr)
-		} else {
-			log.Fatalf("bad instr at pos %d: %v", m.pc, instr)
+	}
+	return false
+}
+
+// Looks up the instruction and checks its modes before anything is
+// executed, so that a bad instruction has no side effects.
+func (m *machine) decode(instr instruction) (opInfo, error) {
+	info, present := m.ops.lookup(instr.op)
+	if !present {
+		return info, &InstructionError{m.pc, m.data[m.pc], ErrBadOpcode}
+	}
+	for i, md := range instr.modes {
+		if md < pos || md > rel || md == imm && writes(info, i) {
+			return info, &InstructionError{m.pc, m.data[m.pc], ErrBadMode}
 		}
 	}
-	close(m.out)
+	return info, nil
+}
+
+// Executes a single instruction. Returns false if the machine halted or
+// is blocked waiting for input, in which case the pc is left unchanged.
+func (m *machine) step() bool {
+	m.state = Running
+	instr := m.ops.parse(m.data[m.pc])
+	if m.dbg {
+		m.log(instr)
+	}
+	info, err := m.decode(instr)
+	if err != nil {
+		m.err, m.state = err, Halted
+		return false
+	}
+	ok := info.h(m, instr)
+	if m.state != Blocked && m.err == nil {
+		m.steps++
+	}
+	return ok
+}
+
+// Runs the machine in its own goroutine, closing out when it stops and
+// then discarding any more input. The returned function waits for it to
+// stop and returns its error.
+func (m *machine) start(in <-chan int64, out chan<- int64) func() error {
+	done := make(chan struct{})
+	go func() {
+		for m.step() {
+		}
+		close(out)
+		close(done)
+		for range in {
+		}
+	}()
+	return func() error {
+		<-done
+		return m.err
+	}
+}
+
+// Runs a machine that has nobody to report errors to.
+func (m *machine) run() {
+	for m.step() {
+	}
+	if m.err != nil {
+		log.Fatal(m.err)
+	}
 }
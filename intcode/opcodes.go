@@ -13,69 +13,64 @@ const (
 	halt          = 99
 )
 
-func (o Opcode) isValid() bool {
-	switch o {
-	case add:
-		fallthrough
-	case mul:
-		fallthrough
-	case read:
-		fallthrough
-	case print:
-		fallthrough
-	case jmpif:
-		fallthrough
-	case jmpnot:
-		fallthrough
-	case lt:
-		fallthrough
-	case eq:
-		fallthrough
-	case adjrel:
-		fallthrough
-	case halt:
-		return true
-	}
-	return false
+type opInfo struct {
+	name   string
+	arity  int64
+	writes []int
+	h      handler
 }
 
-func (o Opcode) String() string {
-	switch o {
-	case add:
-		return "add"
-	case mul:
-		return "mul"
-	case read:
-		return "read"
-	case print:
-		return "print"
-	case jmpif:
-		return "jmpif"
-	case jmpnot:
-		return "jmpnot"
-	case lt:
-		return "lt"
-	case eq:
-		return "eq"
-	case adjrel:
-		return "adjrel"
-	case halt:
-		return "halt"
+var builtins = map[Opcode]opInfo{
+	add:    {name: "add", arity: 3, writes: []int{2}},
+	mul:    {name: "mul", arity: 3, writes: []int{2}},
+	read:   {name: "read", arity: 1, writes: []int{0}},
+	print:  {name: "print", arity: 1},
+	jmpif:  {name: "jmpif", arity: 2},
+	jmpnot: {name: "jmpnot", arity: 2},
+	lt:     {name: "lt", arity: 3, writes: []int{2}},
+	eq:     {name: "eq", arity: 3, writes: []int{2}},
+	adjrel: {name: "adjrel", arity: 1},
+	halt:   {name: "halt", arity: 0},
+}
+
+// OpSet is an instruction set: the built-in instructions, plus any
+// extensions given to NewOpSet. A set can't be changed once it's made,
+// so machines with different sets can run side by side.
+type OpSet struct {
+	ops map[Opcode]opInfo
+}
+
+// The built-in instructions, used by NewMachine, Run and Disassemble.
+var builtinOps = newBuiltinOps()
+
+func newBuiltinOps() *OpSet {
+	s := &OpSet{ops: make(map[Opcode]opInfo)}
+	for op, info := range builtins {
+		info.h = handlers[op]
+		s.ops[op] = info
 	}
-	return ""
+	return s
+}
+
+func (s *OpSet) lookup(o Opcode) (opInfo, bool) {
+	info, ok := s.ops[o]
+	return info, ok
+}
+
+// Name returns the mnemonic of the instruction with the given opcode, or
+// "" if the set doesn't have one.
+func (s *OpSet) Name(o Opcode) string {
+	return s.ops[o].name
 }
 
-var opcodeToArity = map[Opcode]int64{
-	add:    3,
-	mul:    3,
-	read:   1,
-	print:  1,
-	jmpif:  2,
-	jmpnot: 2,
-	lt:     3,
-	eq:     3,
-	adjrel: 1,
-	halt:   0,
+func lookup(o Opcode) (opInfo, bool) {
+	return builtinOps.lookup(o)
+}
+
+// String returns the mnemonic of a built-in instruction, or "" for
+// other opcodes, including extensions; use OpSet.Name for those.
+func (o Opcode) String() string {
+	return builtinOps.Name(o)
 }
 
 type handler func(m *machine, instr instruction) bool
@@ -84,6 +79,9 @@ var handlers = map[Opcode]handler{
 	add: func(m *machine, instr instruction) bool {
 		l := m.get(m.pc+1, instr.modes[0])
 		r := m.get(m.pc+2, instr.modes[1])
+		if m.checked && addOverflows(l, r) {
+			return m.trap(instr, l, r)
+		}
 		m.set(m.pc+3, l+r, instr.modes[2])
 		m.pc += instr.arity + 1
 		return true
@@ -92,13 +90,21 @@ var handlers = map[Opcode]handler{
 	mul: func(m *machine, instr instruction) bool {
 		l := m.get(m.pc+1, instr.modes[0])
 		r := m.get(m.pc+2, instr.modes[1])
+		if m.checked && mulOverflows(l, r) {
+			return m.trap(instr, l, r)
+		}
 		m.set(m.pc+3, l*r, instr.modes[2])
 		m.pc += instr.arity + 1
 		return true
 	},
 
 	read: func(m *machine, instr instruction) bool {
-		m.set(m.pc+1, m.read(), instr.modes[0])
+		v, ok := m.read()
+		if !ok {
+			m.state = Blocked
+			return false
+		}
+		m.set(m.pc+1, v, instr.modes[0])
 		m.pc += instr.arity + 1
 		return true
 	},
@@ -168,6 +174,7 @@ var handlers = map[Opcode]handler{
 	},
 
 	halt: func(m *machine, instr instruction) bool {
+		m.state = Halted
 		return false
 	},
 }
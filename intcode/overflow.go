@@ -0,0 +1,54 @@
+package intcode
+
+import (
+	"fmt"
+	"math"
+)
+
+// OverflowError describes an arithmetic instruction whose result did not
+// fit in an int64.
+type OverflowError struct {
+	PC       int64
+	Op       Opcode
+	Operands [2]int64
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("overflow at pos %d: %s %d %d", e.PC, e.Op, e.Operands[0], e.Operands[1])
+}
+
+func addOverflows(l, r int64) bool {
+	s := l + r
+	return l > 0 && r > 0 && s < 0 || l < 0 && r < 0 && s >= 0
+}
+
+func mulOverflows(l, r int64) bool {
+	if l == 0 || r == 0 {
+		return false
+	}
+	if l == -1 || r == -1 {
+		return l == math.MinInt64 || r == math.MinInt64
+	}
+	return l*r/r != l
+}
+
+// Halts the machine without executing the instruction.
+func (m *machine) trap(instr instruction, l, r int64) bool {
+	m.err = &OverflowError{m.pc, instr.op, [2]int64{l, r}}
+	m.state = Halted
+	return false
+}
+
+// CheckOverflow makes add and mul trap on signed overflow instead of
+// silently wrapping around. A machine that traps halts before executing
+// the offending instruction, and Err describes it.
+func (vm *Machine) CheckOverflow() {
+	vm.m.checked = true
+}
+
+// Err returns an *OverflowError if the machine trapped, an
+// *InstructionError if it hit an instruction it couldn't execute, and
+// nil otherwise.
+func (vm *Machine) Err() error {
+	return vm.m.err
+}
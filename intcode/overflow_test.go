@@ -0,0 +1,132 @@
+package intcode
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestCheckOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		prog []int64
+		want *OverflowError
+	}{
+		{
+			[]int64{1101, math.MaxInt64, 1, 7, 4, 7, 99, 0},
+			&OverflowError{0, add, [2]int64{math.MaxInt64, 1}},
+		},
+		{
+			[]int64{1101, 0, 0, 11, 1102, 1 << 62, 4, 11, 4, 11, 99, 0},
+			&OverflowError{4, mul, [2]int64{1 << 62, 4}},
+		},
+		{
+			[]int64{1102, -1, math.MinInt64, 7, 4, 7, 99, 0},
+			&OverflowError{0, mul, [2]int64{-1, math.MinInt64}},
+		},
+		// day 9 large numbers, which fit
+		{[]int64{1102, 34915192, 34915192, 7, 4, 7, 99, 0}, nil},
+		{[]int64{104, 1125899906842624, 99}, nil},
+		{[]int64{1101, math.MinInt64, math.MaxInt64, 7, 4, 7, 99, 0}, nil},
+	} {
+		vm := NewMachine(tc.prog)
+		vm.CheckOverflow()
+		if state := vm.Run(); state != Halted {
+			t.Errorf("%v: state = %s, want halted", tc.prog, state)
+		}
+		var err *OverflowError
+		if errors.As(vm.Err(), &err) != (tc.want != nil) || tc.want != nil && *err != *tc.want {
+			t.Errorf("%v: err = %v, want %v", tc.prog, vm.Err(), tc.want)
+		}
+		if tc.want != nil && vm.Steps() != tc.want.PC/4 {
+			t.Errorf("%v: steps = %d, want %d", tc.prog, vm.Steps(), tc.want.PC/4)
+		}
+	}
+}
+
+func TestUncheckedOverflowWraps(t *testing.T) {
+	vm := NewMachine([]int64{1101, math.MaxInt64, 1, 7, 4, 7, 99, 0})
+	vm.Run()
+	if out := vm.Outputs(); vm.Err() != nil || len(out) != 1 || out[0] != math.MinInt64 {
+		t.Errorf("got %v, %v", out, vm.Err())
+	}
+}
+
+func bigs(xs ...string) []*big.Int {
+	var vals []*big.Int
+	for _, x := range xs {
+		v, _ := new(big.Int).SetString(x, 10)
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+func TestBigMachine(t *testing.T) {
+	for _, tc := range []struct {
+		prog []int64
+		in   []int64
+		want []*big.Int
+	}{
+		{[]int64{1102, 1 << 62, 4, 7, 4, 7, 99, 0}, nil, bigs("18446744073709551616")},
+		{[]int64{1101, math.MaxInt64, 1, 7, 4, 7, 99, 0}, nil, bigs("9223372036854775808")},
+		// day 9 quine
+		{
+			[]int64{109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99},
+			nil,
+			bigs("109", "1", "204", "-1", "1001", "100", "1", "100", "1008", "100", "16", "101", "1006", "101", "0", "99"),
+		},
+		// day 7 loop program, up to its second read
+		{loopProg, []int64{5, 0}, bigs("1")},
+	} {
+		vm := NewBigMachine(tc.prog)
+		vm.PushInt64(tc.in...)
+		vm.Run()
+		if vm.Err() != nil {
+			t.Errorf("%v: %v", tc.prog, vm.Err())
+		}
+		if got := vm.Outputs(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: outputs = %v, want %v", tc.prog, got, tc.want)
+		}
+	}
+}
+
+func TestBigMachineBadAddress(t *testing.T) {
+	// compute 2^64 and then jump to it
+	vm := NewBigMachine([]int64{1102, 1 << 62, 4, 7, 105, 1, 7, 0})
+	if vm.Run(); vm.State() != Halted || vm.Err() == nil {
+		t.Errorf("state = %s, err = %v", vm.State(), vm.Err())
+	}
+}
+
+// The arbitrary-precision machine agrees with the int64 one on a full
+// puzzle program.
+func TestBigMachineMatchesMachine(t *testing.T) {
+	prog, err := ReadProgram("../day9/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []int64{1, 2} {
+		vm, bvm := NewMachine(prog), NewBigMachine(prog)
+		vm.CheckOverflow()
+		vm.Push(in)
+		bvm.PushInt64(in)
+		vm.Run()
+		bvm.Run()
+		if vm.Err() != nil || bvm.Err() != nil {
+			t.Fatalf("input %d: errors %v, %v", in, vm.Err(), bvm.Err())
+		}
+		got, want := bvm.Outputs(), vm.Outputs()
+		if len(got) != len(want) {
+			t.Fatalf("input %d: outputs = %v, want %v", in, got, want)
+		}
+		for i := range got {
+			if !got[i].IsInt64() || got[i].Int64() != want[i] {
+				t.Errorf("input %d: output %d = %s, want %d", in, i, got[i], want[i])
+			}
+		}
+		if bvm.Steps() != vm.Steps() {
+			t.Errorf("input %d: steps = %d, want %d", in, bvm.Steps(), vm.Steps())
+		}
+	}
+}
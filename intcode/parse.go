@@ -0,0 +1,123 @@
+package intcode
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A program image starts with imageMagic, followed by the number of
+// values as a uvarint and then each value as a varint. Text programs
+// can't start with a letter, so the formats can't be confused.
+const imageMagic = "INTC"
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Parse reads a program in any of the supported formats: comma-separated
+// text, a binary image as written by WriteImage, or either of those
+// compressed with gzip.
+//
+// Text may have whitespace and newlines around each value and a trailing
+// comma. Errors give the index of the bad token, which is also its
+// address in the program.
+func Parse(r io.Reader) ([]int64, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+	if magic, _ := br.Peek(len(imageMagic)); string(magic) == imageMagic {
+		br.Discard(len(imageMagic))
+		return parseImage(br)
+	}
+	return parseText(br)
+}
+
+// ParseString reads a program from s.
+func ParseString(s string) ([]int64, error) {
+	return Parse(strings.NewReader(s))
+}
+
+func parseText(br *bufio.Reader) ([]int64, error) {
+	var data []int64
+	for i := 0; ; i++ {
+		tok, err := br.ReadString(',')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		tok = strings.TrimSpace(strings.TrimSuffix(tok, ","))
+		if tok == "" && err == io.EOF {
+			break
+		}
+		v, perr := strconv.ParseInt(tok, 10, 64)
+		if perr != nil {
+			return nil, fmt.Errorf("token %d: bad int %q", i, tok)
+		}
+		data = append(data, v)
+		if err == io.EOF {
+			break
+		}
+	}
+	return data, nil
+}
+
+func parseImage(br *bufio.Reader) ([]int64, error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("bad image length: %w", err)
+	}
+	// don't trust the length for more than a guess at the capacity
+	c := n
+	if c > 1<<20 {
+		c = 1 << 20
+	}
+	data := make([]int64, 0, c)
+	for i := uint64(0); i < n; i++ {
+		v, err := binary.ReadVarint(br)
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		data = append(data, v)
+	}
+	return data, nil
+}
+
+// WriteImage writes data as a binary image, which is smaller and much
+// faster to parse than text.
+func WriteImage(w io.Writer, data []int64) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(imageMagic)
+	buf := make([]byte, binary.MaxVarintLen64)
+	bw.Write(buf[:binary.PutUvarint(buf, uint64(len(data)))])
+	for _, v := range data {
+		bw.Write(buf[:binary.PutVarint(buf, v)])
+	}
+	return bw.Flush()
+}
+
+func ReadProgram(path string) ([]int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,119 @@
+package intcode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseText(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want []int64
+		err  string
+	}{
+		{"1,0,0,0,99\n", []int64{1, 0, 0, 0, 99}, ""},
+		{"1, 0,\n0 ,\t0,\r\n99,\n", []int64{1, 0, 0, 0, 99}, ""},
+		{"104,-1125899906842624,99", []int64{104, -1125899906842624, 99}, ""},
+		{"  \n", nil, ""},
+		{"", nil, ""},
+		{"1,,2", nil, `token 1: bad int ""`},
+		{"1,2 3,4", nil, `token 1: bad int "2 3"`},
+		{"1,2,x", nil, `token 2: bad int "x"`},
+		{"1,2,,", nil, `token 2: bad int ""`},
+		{"9223372036854775808", nil, `token 0: bad int "9223372036854775808"`},
+	} {
+		got, err := ParseString(tc.s)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("ParseString(%q) err = %v, want %q", tc.s, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseString(%q) = %v, %v, want %v", tc.s, got, err, tc.want)
+		}
+	}
+}
+
+func gzipped(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseFormats(t *testing.T) {
+	text, err := os.ReadFile("../day9/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ParseString(string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var image bytes.Buffer
+	if err := WriteImage(&image, want); err != nil {
+		t.Fatal(err)
+	}
+	if image.Len() >= len(text) {
+		t.Errorf("image is %d bytes, text is %d", image.Len(), len(text))
+	}
+	for name, b := range map[string][]byte{
+		"image":      image.Bytes(),
+		"gzip text":  gzipped(t, text),
+		"gzip image": gzipped(t, image.Bytes()),
+	} {
+		got, err := Parse(bytes.NewReader(b))
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Parse = %v, %v, want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestParseBadImage(t *testing.T) {
+	var image bytes.Buffer
+	if err := WriteImage(&image, []int64{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	b := image.Bytes()
+	if _, err := Parse(bytes.NewReader(b[:len(b)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated image: err = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	if _, err := Parse(bytes.NewReader(gzipped(t, b)[:10])); err == nil {
+		t.Error("truncated gzip: no error")
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	text, err := os.ReadFile("../day9/input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := ParseString(string(text))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var image bytes.Buffer
+	if err := WriteImage(&image, data); err != nil {
+		b.Fatal(err)
+	}
+	for name, in := range map[string][]byte{"text": text, "image": image.Bytes()} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(bytes.NewReader(in)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
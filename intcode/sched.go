@@ -0,0 +1,138 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+var ErrDeadlock = errors.New("deadlock")
+
+// Scheduler runs several Machines cooperatively on a single goroutine.
+// Each round gives every machine a turn, either in order or in a seeded
+// random order, so that a simulation can be replayed exactly and a
+// deadlock is detected as soon as a round passes without progress.
+type Scheduler struct {
+	Machines []*Machine
+
+	// Quantum bounds the number of instructions executed per turn. If
+	// zero, a machine runs until it blocks or halts.
+	Quantum int
+
+	// Starved is called when a machine's turn comes up while it is
+	// blocked with no input queued. It may push input to the machine;
+	// otherwise the machine sits out the round.
+	Starved func(i int)
+
+	// Output is called with the values a machine wrote during its turn.
+	Output func(i int, out []int64)
+
+	Rounds  int
+	rng     *rand.Rand
+	stopped bool
+}
+
+func NewScheduler(ms ...*Machine) *Scheduler {
+	return &Scheduler{Machines: ms}
+}
+
+// Randomize visits the machines in a random order each round, seeded so
+// that the schedule is reproducible.
+func (s *Scheduler) Randomize(seed int64) {
+	s.rng = rand.New(rand.NewSource(seed))
+}
+
+// Stop ends the current round after the running turn finishes and
+// causes Run to return.
+func (s *Scheduler) Stop() {
+	s.stopped = true
+}
+
+func (s *Scheduler) order() []int {
+	if s.rng != nil {
+		return s.rng.Perm(len(s.Machines))
+	}
+	order := make([]int, len(s.Machines))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func (s *Scheduler) turn(i int) bool {
+	vm := s.Machines[i]
+	if vm.State() == Halted {
+		return false
+	}
+	if vm.State() == Blocked && vm.Queued() == 0 && s.Starved != nil {
+		s.Starved(i)
+	}
+	before := vm.Steps()
+	if s.Quantum == 0 {
+		vm.Run()
+	} else {
+		for n := 0; n < s.Quantum && vm.Step() == Running; n++ {
+		}
+	}
+	if out := vm.Outputs(); len(out) > 0 && s.Output != nil {
+		s.Output(i, out)
+	}
+	return vm.Steps() > before
+}
+
+// Round gives each machine one turn and reports whether any of them
+// executed an instruction.
+func (s *Scheduler) Round() bool {
+	s.Rounds++
+	progress := false
+	for _, i := range s.order() {
+		if s.turn(i) {
+			progress = true
+		}
+		if s.stopped {
+			break
+		}
+	}
+	return progress
+}
+
+func (s *Scheduler) blocked() []int {
+	var blocked []int
+	for i, vm := range s.Machines {
+		if vm.State() != Halted {
+			blocked = append(blocked, i)
+		}
+	}
+	return blocked
+}
+
+// Err returns the error of the first machine, in index order, that
+// stopped with one, wrapped with its index.
+func (s *Scheduler) Err() error {
+	for i, vm := range s.Machines {
+		if err := vm.Err(); err != nil {
+			return fmt.Errorf("machine %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Run executes rounds until every machine halts or Stop is called. It
+// returns a machine's error as soon as one stops with an error, as
+// described by Err, and ErrDeadlock if the remaining machines are all
+// blocked on input that will never arrive.
+func (s *Scheduler) Run() error {
+	for !s.stopped {
+		progress := s.Round()
+		if err := s.Err(); err != nil {
+			return err
+		}
+		if !progress && !s.stopped {
+			if blocked := s.blocked(); len(blocked) > 0 {
+				return fmt.Errorf("%w: machines %v blocked on input", ErrDeadlock, blocked)
+			}
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package intcode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// day 7 feedback loop example
+var loopProg = []int64{
+	3, 26, 1001, 26, -4, 26, 3, 27, 1002, 27, 2, 27, 1, 27, 26, 27, 4, 27,
+	1001, 28, -1, 28, 1005, 28, 6, 99, 0, 0, 5,
+}
+
+func feedbackLoop(phases []int64) (*Scheduler, *int64) {
+	vms := make([]*Machine, len(phases))
+	for i, phase := range phases {
+		vms[i] = NewMachine(loopProg)
+		vms[i].Push(phase)
+	}
+	vms[0].Push(0)
+	s := NewScheduler(vms...)
+	var last int64
+	s.Output = func(i int, out []int64) {
+		if i == len(vms)-1 {
+			last = out[len(out)-1]
+		}
+		vms[(i+1)%len(vms)].Push(out...)
+	}
+	return s, &last
+}
+
+func TestSchedulerFeedbackLoop(t *testing.T) {
+	for _, quantum := range []int{0, 1, 7} {
+		for _, seed := range []int64{-1, 1, 42} {
+			s, last := feedbackLoop([]int64{9, 8, 7, 6, 5})
+			s.Quantum = quantum
+			if seed >= 0 {
+				s.Randomize(seed)
+			}
+			if err := s.Run(); err != nil {
+				t.Fatal(err)
+			}
+			if *last != 139629729 {
+				t.Errorf("quantum %d, seed %d: signal = %d, want 139629729", quantum, seed, *last)
+			}
+		}
+	}
+}
+
+func TestSchedulerDeadlock(t *testing.T) {
+	s := NewScheduler(NewMachine([]int64{3, 0, 99}), NewMachine([]int64{99}))
+	if err := s.Run(); !errors.Is(err, ErrDeadlock) {
+		t.Errorf("Run() = %v, want %v", err, ErrDeadlock)
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	loop := []int64{4, 0, 1105, 1, 0} // print forever
+	s := NewScheduler(NewMachine(loop))
+	s.Quantum = 10
+	n := 0
+	s.Output = func(i int, out []int64) {
+		if n += len(out); n >= 20 {
+			s.Stop()
+		}
+	}
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Rounds != 4 {
+		t.Errorf("Rounds = %d, want 4", s.Rounds)
+	}
+}
+
+func TestSchedulerError(t *testing.T) {
+	// the second machine hits opcode 77 after printing
+	s := NewScheduler(NewMachine([]int64{3, 0, 99}), NewMachine([]int64{104, 5, 77}))
+	s.Output = func(i int, out []int64) {
+		s.Machines[0].Push(out...)
+	}
+	err := s.Run()
+	var ierr *InstructionError
+	if !errors.As(err, &ierr) || !errors.Is(err, ErrBadOpcode) {
+		t.Fatalf("Run() = %v, want a bad opcode", err)
+	}
+	if !strings.HasPrefix(err.Error(), "machine 1: ") {
+		t.Errorf("Run() = %q, want the machine index", err)
+	}
+}
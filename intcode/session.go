@@ -0,0 +1,213 @@
+package intcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type EventKind int
+
+const (
+	Input EventKind = iota + 1
+	Output
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Input:
+		return "in"
+	case Output:
+		return "out"
+	}
+	return ""
+}
+
+// Event is a single value read or written by a machine. Step is the
+// number of instructions the machine had executed before the read or
+// print instruction that produced the event.
+type Event struct {
+	Kind  EventKind
+	Step  int64
+	Value int64
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %d %d", e.Kind, e.Step, e.Value)
+}
+
+// Session is the log of every input and output of a recorded run.
+type Session struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *Session) add(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+}
+
+// Events returns a copy of the events recorded so far.
+func (s *Session) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+// WriteTo writes the session as text, one event per line.
+func (s *Session) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var n int64
+	for _, e := range s.Events() {
+		m, err := fmt.Fprintln(bw, e)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, bw.Flush()
+}
+
+// WriteFile writes the session to the named file, as with WriteTo.
+func (s *Session) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := s.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func parseEvent(line string) (Event, error) {
+	var e Event
+	toks := strings.Fields(line)
+	if len(toks) != 3 {
+		return e, fmt.Errorf("want 3 fields, got %d", len(toks))
+	}
+	switch toks[0] {
+	case "in":
+		e.Kind = Input
+	case "out":
+		e.Kind = Output
+	default:
+		return e, fmt.Errorf("bad event kind: %q", toks[0])
+	}
+	var err error
+	if e.Step, err = strconv.ParseInt(toks[1], 10, 64); err != nil {
+		return e, err
+	}
+	if e.Value, err = strconv.ParseInt(toks[2], 10, 64); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// ReadSession parses a session written by WriteTo. Blank lines and
+// lines starting with '#' are ignored.
+func ReadSession(r io.Reader) (*Session, error) {
+	s := &Session{}
+	scan := bufio.NewScanner(r)
+	for n := 1; scan.Scan(); n++ {
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := parseEvent(line)
+		if err != nil {
+			return nil, fmt.Errorf("bad event on line %d: %w", n, err)
+		}
+		s.events = append(s.events, e)
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record is like Start, but logs every value read and written by the
+// machine to the given session.
+func Record(data []int64, in <-chan int64, s *Session) (out <-chan int64, wait func() error) {
+	ch := make(chan int64)
+	m := newChanMachine(data, in, ch, false)
+	read, write := m.in, m.out
+	m.in = func() (int64, bool) {
+		v, ok := read()
+		if ok {
+			s.add(Event{Input, m.steps, v})
+		}
+		return v, ok
+	}
+	m.out = func(v int64) {
+		s.add(Event{Output, m.steps, v})
+		write(v)
+	}
+	return ch, m.start(in, ch)
+}
+
+// DivergenceError describes the first point at which a replayed run
+// stopped matching its session. Want is the zero Event if the run went
+// on past the end of the session, and Got is the zero Event if the
+// program halted before reaching the end.
+type DivergenceError struct {
+	Index     int
+	Want, Got Event
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("diverged at event %d: want [%s], got [%s]", e.Index, e.Want, e.Got)
+}
+
+// Replay runs data, feeding it the inputs recorded in the session, and
+// checks that it reads and writes exactly the recorded values at exactly
+// the recorded steps. If it doesn't, the error is a *DivergenceError.
+// A session may end with the program waiting for input, as when an
+// interactive run is cut short. If the program stops with an error after
+// the last event, Replay returns that error, as described by Machine.Err.
+func Replay(data []int64, s *Session) error {
+	events := s.Events()
+	vm := NewMachine(data)
+	i := 0
+	check := func(got Event) error {
+		if i >= len(events) || events[i] != got {
+			e := &DivergenceError{Index: i, Got: got}
+			if i < len(events) {
+				e.Want = events[i]
+			}
+			return e
+		}
+		i++
+		return nil
+	}
+	for {
+		step := vm.Steps()
+		switch vm.Step() {
+		case Blocked:
+			if i == len(events) {
+				return nil
+			}
+			want := Event{Input, step, events[i].Value}
+			if err := check(want); err != nil {
+				return err
+			}
+			vm.Push(want.Value)
+		case Halted:
+			if i < len(events) {
+				return &DivergenceError{Index: i, Want: events[i]}
+			}
+			return vm.Err()
+		}
+		for _, v := range vm.Outputs() {
+			if err := check(Event{Output, step, v}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package intcode
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// reads n, then prints n, n-1, ..., 1
+var countdown = []int64{3, 12, 4, 12, 1001, 12, -1, 12, 1005, 12, 2, 99, 0}
+
+func record(t *testing.T, data []int64, inputs ...int64) *Session {
+	t.Helper()
+	in := make(chan int64, len(inputs))
+	for _, v := range inputs {
+		in <- v
+	}
+	s := &Session{}
+	out, wait := Record(data, in, s)
+	for range out {
+	}
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	if len(in) > 0 {
+		t.Fatalf("program left %d of %d inputs unread", len(in), len(inputs))
+	}
+	return s
+}
+
+func TestRecord(t *testing.T) {
+	s := record(t, countdown, 3)
+	want := []Event{
+		{Input, 0, 3},
+		{Output, 1, 3},
+		{Output, 4, 2},
+		{Output, 7, 1},
+	}
+	if got := s.Events(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Events() = %v, want %v", got, want)
+	}
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	s := record(t, countdown, 5)
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := ReadSession(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Events(), s2.Events()) {
+		t.Errorf("ReadSession = %v, want %v", s2.Events(), s.Events())
+	}
+	if err := Replay(countdown, s2); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadSessionError(t *testing.T) {
+	_, err := ReadSession(bytes.NewBufferString("# comment\nin 0 3\nout x 1\n"))
+	if err == nil || err.Error() != `bad event on line 3: strconv.ParseInt: parsing "x": invalid syntax` {
+		t.Errorf("ReadSession error = %v", err)
+	}
+}
+
+func TestReplayDivergence(t *testing.T) {
+	s := record(t, countdown, 3)
+	modified := append([]int64(nil), countdown...)
+	modified[6] = -2 // count down by two
+	err := Replay(modified, s)
+	var div *DivergenceError
+	if !errors.As(err, &div) {
+		t.Fatalf("Replay() = %v, want divergence", err)
+	}
+	want := DivergenceError{2, Event{Output, 4, 2}, Event{Output, 4, 1}}
+	if *div != want {
+		t.Errorf("Replay() = %v, want %v", div, &want)
+	}
+}
+
+func TestReplayTruncated(t *testing.T) {
+	s := record(t, countdown, 3)
+	s.events = s.events[:2]
+	var div *DivergenceError
+	if err := Replay(countdown, s); !errors.As(err, &div) || div.Index != 2 || div.Want != (Event{}) {
+		t.Errorf("Replay() = %v, want divergence past the end at event 2", err)
+	}
+}
+
+func TestReplayError(t *testing.T) {
+	s := record(t, countdown, 3)
+	broken := append([]int64(nil), countdown...)
+	broken[11] = 77 // fail instead of halting after the last output
+	if err := Replay(broken, s); !errors.Is(err, ErrBadOpcode) {
+		t.Errorf("Replay() = %v, want %v", err, ErrBadOpcode)
+	}
+}
+
+func TestSessionWriteFile(t *testing.T) {
+	s := record(t, countdown, 2)
+	path := filepath.Join(t.TempDir(), "session.txt")
+	if err := s.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s2, err := ReadSession(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Events(), s2.Events()) {
+		t.Errorf("read back %v, want %v", s2.Events(), s.Events())
+	}
+}
+
+func TestReplayInterrupted(t *testing.T) {
+	// a session cut short while the program waits for input
+	if err := Replay(countdown, &Session{}); err != nil {
+		t.Errorf("Replay() = %v, want success", err)
+	}
+}
@@ -0,0 +1,88 @@
+package intcode
+
+// Machine is an intcode machine driven directly by its caller instead of
+// over channels. Input is queued with Push, and output accumulates until
+// it is collected with Outputs. A machine that reads with no input queued
+// becomes Blocked until more input is pushed and it is stepped again.
+type Machine struct {
+	m   *machine
+	inq []int64
+	out []int64
+}
+
+func NewMachine(data []int64) *Machine {
+	return builtinOps.NewMachine(data)
+}
+
+// NewMachine returns a machine that runs the instructions in s.
+func (s *OpSet) NewMachine(data []int64) *Machine {
+	vm := &Machine{m: newMachine(data, false)}
+	vm.m.ops = s
+	vm.m.in = func() (int64, bool) {
+		if len(vm.inq) == 0 {
+			return 0, false
+		}
+		v := vm.inq[0]
+		vm.inq = vm.inq[1:]
+		return v, true
+	}
+	vm.m.out = func(v int64) {
+		vm.out = append(vm.out, v)
+	}
+	return vm
+}
+
+func (vm *Machine) Push(vals ...int64) {
+	vm.inq = append(vm.inq, vals...)
+}
+
+// Queued returns the number of input values not yet read.
+func (vm *Machine) Queued() int {
+	return len(vm.inq)
+}
+
+// Outputs returns the values written since the last call.
+func (vm *Machine) Outputs() []int64 {
+	out := vm.out
+	vm.out = nil
+	return out
+}
+
+func (vm *Machine) State() State {
+	return vm.m.state
+}
+
+// Steps returns the number of instructions executed so far.
+func (vm *Machine) Steps() int64 {
+	return vm.m.steps
+}
+
+// PC returns the address of the next instruction.
+func (vm *Machine) PC() int64 {
+	return vm.m.pc
+}
+
+// RelBase returns the base address for relative mode.
+func (vm *Machine) RelBase() int64 {
+	return vm.m.relbase
+}
+
+// Mem returns the value stored at addr.
+func (vm *Machine) Mem(addr int64) int64 {
+	return vm.m.data[addr]
+}
+
+// Step executes a single instruction, unless the machine has halted.
+func (vm *Machine) Step() State {
+	if vm.m.state != Halted {
+		vm.m.step()
+	}
+	return vm.m.state
+}
+
+// Run executes instructions until the machine halts or blocks on input.
+func (vm *Machine) Run() State {
+	for vm.Step() == Running {
+	}
+	return vm.m.state
+}
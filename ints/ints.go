@@ -1,56 +1,93 @@
+// Package ints provides arithmetic helpers for the built-in integer
+// types.
 package ints
 
-func Max(x, y int) int {
-	if x >= y {
-		return x
-	}
-	return y
+// Integer is any integer type. It matches constraints.Integer from
+// golang.org/x/exp.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
 }
 
-func Max64(x, y int64) int64 {
-	if x >= y {
-		return x
+// Max returns the largest of its arguments.
+func Max[T Integer](x T, xs ...T) T {
+	for _, y := range xs {
+		if y > x {
+			x = y
+		}
 	}
-	return y
+	return x
 }
 
-func Min(x, y int) int {
-	if x <= y {
-		return x
+// Min returns the smallest of its arguments.
+func Min[T Integer](x T, xs ...T) T {
+	for _, y := range xs {
+		if y < x {
+			x = y
+		}
 	}
-	return y
+	return x
 }
 
-func Abs(x int) int {
+func Abs[T Integer](x T) T {
 	if x < 0 {
 		return -x
 	}
 	return x
 }
 
-func Abs64(x int64) int64 {
-	if x < 0 {
-		return -x
+// Sign returns -1, 0 or 1 according to whether x is negative, zero or
+// positive.
+func Sign[T Integer](x T) T {
+	switch {
+	case x < 0:
+		return ^T(0) // -1 without a constant conversion
+	case x > 0:
+		return 1
 	}
-	return x
+	return 0
+}
+
+// Clamp returns the value in [lo, hi] closest to x.
+func Clamp[T Integer](x, lo, hi T) T {
+	return Max(lo, Min(x, hi))
+}
+
+func Gcd[T Integer](m, n T) T {
+	for n != 0 {
+		m, n = n, m%n
+	}
+	return m
+}
+
+// DivCeil returns m/n rounded up instead of toward zero.
+func DivCeil[T Integer](m, n T) T {
+	q := m / n
+	if m%n != 0 && (m < 0) == (n < 0) {
+		q++
+	}
+	return q
 }
 
-func Gcd(m, n int) int {
-	if n == 0 {
-		return m
+func Sum[T Integer](xs ...T) T {
+	var s T
+	for _, x := range xs {
+		s += x
 	}
-	return Gcd(n, m%n)
+	return s
 }
 
-func Gcd64(m, n int64) int64 {
-	if n == 0 {
-		return m
+func Product[T Integer](xs ...T) T {
+	p := T(1)
+	for _, x := range xs {
+		p *= x
 	}
-	return Gcd64(n, m%n)
+	return p
 }
 
-func Copied64(x []int64) []int64 {
-	y := make([]int64, len(x))
+// Copied returns a copy of x.
+func Copied[T Integer](x []T) []T {
+	y := make([]T, len(x))
 	copy(y, x)
 	return y
 }
@@ -15,3 +15,56 @@ func TestAbs(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneric(t *testing.T) {
+	if got := Max(3, 9, -2); got != 9 {
+		t.Errorf("Max = %d, want 9", got)
+	}
+	if got := Min(int64(3), 9, -2); got != -2 {
+		t.Errorf("Min = %d, want -2", got)
+	}
+	if got := Abs(int8(-5)); got != 5 {
+		t.Errorf("Abs = %d, want 5", got)
+	}
+	for x, want := range map[int]int{-7: -1, 0: 0, 12: 1} {
+		if got := Sign(x); got != want {
+			t.Errorf("Sign(%d) = %d, want %d", x, got, want)
+		}
+	}
+	if got := Sign(uint(3)); got != 1 {
+		t.Errorf("Sign(uint) = %d, want 1", got)
+	}
+	for x, want := range map[int]int{5: 3, -1: 0, 2: 2} {
+		if got := Clamp(x, 0, 3); got != want {
+			t.Errorf("Clamp(%d, 0, 3) = %d, want %d", x, got, want)
+		}
+	}
+	if got := Gcd(uint32(84), 36); got != 12 {
+		t.Errorf("Gcd = %d, want 12", got)
+	}
+	if got := Sum[int](); got != 0 {
+		t.Errorf("Sum() = %d, want 0", got)
+	}
+	if got := Sum(1, 2, 3, 4); got != 10 {
+		t.Errorf("Sum = %d, want 10", got)
+	}
+	if got := Product(int64(2), 3, 7); got != 42 {
+		t.Errorf("Product = %d, want 42", got)
+	}
+	x := []rune("abc")
+	y := Copied(x)
+	y[0] = 'z'
+	if string(x) != "abc" || string(y) != "zbc" {
+		t.Errorf("Copied shares storage: %q, %q", string(x), string(y))
+	}
+}
+
+func TestDivCeil(t *testing.T) {
+	for _, tc := range [][3]int{
+		{7, 2, 4}, {8, 2, 4}, {0, 3, 0}, {-7, 2, -3}, {7, -2, -3}, {-7, -2, 4}, {1, 10, 1},
+	} {
+		if got := DivCeil(tc[0], tc[1]); got != tc[2] {
+			t.Errorf("DivCeil(%d, %d) = %d, want %d", tc[0], tc[1], got, tc[2])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ints
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Lcm returns the least common multiple of its arguments, or 1 if there
+// are none. It fails if the result doesn't fit in an int64.
+func Lcm(xs ...int64) (int64, bool) {
+	l := int64(1)
+	for _, x := range xs {
+		if x == 0 {
+			return 0, true
+		}
+		hi, lo := bits.Mul64(uint64(l/Abs(Gcd(l, x))), uint64(Abs(x)))
+		if hi != 0 || lo > math.MaxInt64 {
+			return 0, false
+		}
+		l = int64(lo)
+	}
+	return l, true
+}
+
+// ExtGcd returns g = gcd(a, b) along with Bézout coefficients x and y
+// such that a*x + b*y = g.
+func ExtGcd(a, b int64) (g, x, y int64) {
+	x0, x1, y0, y1 := int64(1), int64(0), int64(0), int64(1)
+	for b != 0 {
+		q := a / b
+		a, b = b, a-q*b
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+	if a < 0 {
+		return -a, -x0, -y0
+	}
+	return a, x0, y0
+}
+
+// Mod returns a modulo m in the range [0, m). The modulus must be
+// positive.
+func Mod(a, m int64) int64 {
+	a %= m
+	if a < 0 {
+		a += m
+	}
+	return a
+}
+
+// AddMod returns a+b modulo m without overflowing, for any positive
+// modulus.
+func AddMod(a, b, m int64) int64 {
+	a, b = Mod(a, m), Mod(b, m)
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
+
+// SubMod returns a-b modulo m without overflowing, for any positive
+// modulus.
+func SubMod(a, b, m int64) int64 {
+	a, b = Mod(a, m), Mod(b, m)
+	if a < b {
+		return m - (b - a)
+	}
+	return a - b
+}
+
+// MulMod returns a*b modulo m without overflowing, for any positive
+// modulus.
+func MulMod(a, b, m int64) int64 {
+	hi, lo := bits.Mul64(uint64(Mod(a, m)), uint64(Mod(b, m)))
+	return int64(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// ModPow returns b to the power e modulo m. The exponent must be
+// non-negative.
+func ModPow(b, e, m int64) int64 {
+	r := Mod(1, m)
+	for b = Mod(b, m); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = MulMod(r, b, m)
+		}
+		b = MulMod(b, b, m)
+	}
+	return r
+}
+
+// ModInverse returns the x in [0, m) for which a*x is 1 modulo m. It
+// fails if a and m are not coprime.
+func ModInverse(a, m int64) (int64, bool) {
+	g, x, _ := ExtGcd(Mod(a, m), m)
+	if g != 1 {
+		return 0, false
+	}
+	return Mod(x, m), true
+}
+
+// CRT solves the system of congruences x = rems[i] modulo mods[i] using
+// the Chinese Remainder Theorem. The moduli must be positive but need
+// not be coprime. It returns the smallest non-negative solution and the
+// least common multiple of the moduli, which every solution is equal to
+// modulo. It fails if there is no solution, if rems and mods have
+// different lengths, or if the least common multiple doesn't fit in an
+// int64.
+func CRT(rems, mods []int64) (x, m int64, ok bool) {
+	if len(rems) != len(mods) {
+		return 0, 0, false
+	}
+	x, m = 0, 1
+	for i, mi := range mods {
+		ri := Mod(rems[i], mi)
+		g, p, _ := ExtGcd(m, mi)
+		if (ri-x)%g != 0 {
+			return 0, 0, false
+		}
+		// x + m*k = ri (mod mi), so k = (ri-x)/g * p (mod mi/g)
+		step := mi / g
+		k := MulMod((ri-x)/g, p, step)
+		hi, lo := bits.Mul64(uint64(m), uint64(step))
+		if hi != 0 || lo > math.MaxInt64 {
+			return 0, 0, false
+		}
+		l := int64(lo)
+		x = AddMod(x, MulMod(m, k, l), l)
+		m = l
+	}
+	return x, m, true
+}
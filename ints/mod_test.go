@@ -0,0 +1,114 @@
+package ints
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestLcm(t *testing.T) {
+	for _, tc := range []struct {
+		xs   []int64
+		want int64
+		ok   bool
+	}{
+		{nil, 1, true},
+		{[]int64{6}, 6, true},
+		{[]int64{4, 6}, 12, true},
+		{[]int64{18, 28, 44}, 2772, true},
+		{[]int64{-4, 6}, 12, true},
+		{[]int64{1 << 62, 3}, 0, false},
+		{[]int64{1<<61 - 1, 4}, 1<<63 - 4, true},
+		{[]int64{math.MinInt64}, 0, false},
+	} {
+		if got, ok := Lcm(tc.xs...); got != tc.want || ok != tc.ok {
+			t.Errorf("Lcm(%v) = %d, %t, want %d, %t", tc.xs, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestExtGcd(t *testing.T) {
+	for _, tc := range [][2]int64{{240, 46}, {46, 240}, {17, 5}, {0, 9}, {-12, 18}, {7, 0}} {
+		a, b := tc[0], tc[1]
+		g, x, y := ExtGcd(a, b)
+		if g != Abs(Gcd(a, b)) || a*x+b*y != g {
+			t.Errorf("ExtGcd(%d, %d) = %d, %d, %d", a, b, g, x, y)
+		}
+	}
+}
+
+func TestMulMod(t *testing.T) {
+	const m = math.MaxInt64 - 24 // near 2^63
+	for _, tc := range [][2]int64{{m - 1, m - 1}, {-3, m - 2}, {1 << 62, 1 << 61}, {0, 5}} {
+		want := new(big.Int).Mul(big.NewInt(tc[0]), big.NewInt(tc[1]))
+		want.Mod(want, big.NewInt(m))
+		if got := MulMod(tc[0], tc[1], m); got != want.Int64() {
+			t.Errorf("MulMod(%d, %d) = %d, want %s", tc[0], tc[1], got, want)
+		}
+	}
+}
+
+func TestAddSubMod(t *testing.T) {
+	const m = math.MaxInt64 - 24 // near 2^63
+	for _, tc := range [][2]int64{{m - 1, m - 1}, {m - 1, 1}, {-3, m - 2}, {1 << 62, 1 << 62}, {0, 5}, {5, 0}} {
+		a, b, bm := big.NewInt(tc[0]), big.NewInt(tc[1]), big.NewInt(m)
+		sum := new(big.Int).Mod(new(big.Int).Add(a, b), bm)
+		if got := AddMod(tc[0], tc[1], m); got != sum.Int64() {
+			t.Errorf("AddMod(%d, %d) = %d, want %s", tc[0], tc[1], got, sum)
+		}
+		diff := new(big.Int).Mod(new(big.Int).Sub(a, b), bm)
+		if got := SubMod(tc[0], tc[1], m); got != diff.Int64() {
+			t.Errorf("SubMod(%d, %d) = %d, want %s", tc[0], tc[1], got, diff)
+		}
+	}
+}
+
+func TestModPow(t *testing.T) {
+	const m = 119315717514047
+	for _, tc := range [][2]int64{{2, 0}, {3, 13}, {-5, 101741582076661}, {m - 1, m - 1}} {
+		want := new(big.Int).Exp(big.NewInt(tc[0]), big.NewInt(tc[1]), big.NewInt(m))
+		if got := ModPow(tc[0], tc[1], m); got != want.Int64() {
+			t.Errorf("ModPow(%d, %d) = %d, want %s", tc[0], tc[1], got, want)
+		}
+	}
+	if got := ModPow(5, 0, 1); got != 0 {
+		t.Errorf("ModPow(5, 0, 1) = %d, want 0", got)
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	const m = 119315717514047
+	for _, a := range []int64{1, 2, -7, m - 1, 48710243} {
+		x, ok := ModInverse(a, m)
+		if !ok || MulMod(a, x, m) != 1 {
+			t.Errorf("ModInverse(%d) = %d, %t", a, x, ok)
+		}
+	}
+	if _, ok := ModInverse(6, 9); ok {
+		t.Errorf("ModInverse(6, 9) succeeded")
+	}
+}
+
+func TestCRT(t *testing.T) {
+	for _, tc := range []struct {
+		rems, mods []int64
+		x, m       int64
+		ok         bool
+	}{
+		{nil, nil, 0, 1, true},
+		{[]int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105, true},
+		{[]int64{0, -2, -3}, []int64{17, 13, 19}, 3417, 4199, true},
+		{[]int64{3, 5}, []int64{4, 6}, 11, 12, true},
+		{[]int64{1, 2}, []int64{4, 6}, 0, 0, false},
+		{[]int64{1, 2}, []int64{1<<61 - 1, 3}, 2305843009213693952, 6917529027641081853, true},
+		{[]int64{1, 2}, []int64{1<<61 - 1, 5}, 0, 0, false}, // lcm overflows
+		{[]int64{1}, []int64{3, 5}, 0, 0, false},
+		{[]int64{1, 2}, []int64{3}, 0, 0, false},
+	} {
+		x, m, ok := CRT(tc.rems, tc.mods)
+		if x != tc.x || m != tc.m || ok != tc.ok {
+			t.Errorf("CRT(%v, %v) = %d, %d, %t; want %d, %d, %t",
+				tc.rems, tc.mods, x, m, ok, tc.x, tc.m, tc.ok)
+		}
+	}
+}
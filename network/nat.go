@@ -0,0 +1,48 @@
+package network
+
+// NAT remembers the last packet sent to it and, whenever the network
+// goes idle, forwards that packet's contents to address 0. It stops the
+// network the first time it would forward the same Y value twice in a
+// row.
+type NAT struct {
+	First, Last Packet
+	Received    int
+	Sent        []int64 // Y values forwarded to address 0
+	Repeated    bool
+}
+
+func (n *NAT) Receive(p Packet) bool {
+	if n.Received == 0 {
+		n.First = p
+	}
+	n.Last = p
+	n.Received++
+	return false
+}
+
+func (n *NAT) Idle() ([]Packet, bool) {
+	if n.Received == 0 {
+		return nil, false
+	}
+	if len(n.Sent) > 0 && n.Sent[len(n.Sent)-1] == n.Last.Y {
+		n.Repeated = true
+		return nil, true
+	}
+	n.Sent = append(n.Sent, n.Last.Y)
+	return []Packet{{Dest: 0, X: n.Last.X, Y: n.Last.Y}}, false
+}
+
+// Sink stops the network as soon as it receives a packet.
+type Sink struct {
+	Packet
+	Received bool
+}
+
+func (s *Sink) Receive(p Packet) bool {
+	s.Packet, s.Received = p, true
+	return true
+}
+
+func (s *Sink) Idle() ([]Packet, bool) {
+	return nil, false
+}
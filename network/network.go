@@ -0,0 +1,180 @@
+// Package network simulates a packet-switched network of intcode
+// computers, as in day 23. Each node is booted with its address and then
+// sends and receives packets as (destination, x, y) triples, reading -1
+// whenever it has nothing queued.
+//
+// The simulation runs the nodes with an intcode.Scheduler on a single
+// goroutine, so every run is reproducible and the network is known to
+// be idle exactly when a full round passes in which every node polled
+// an empty queue and none of them sent a packet.
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+type Packet struct {
+	Src, Dest int64
+	X, Y      int64
+}
+
+func (p Packet) String() string {
+	return fmt.Sprintf("%d -> %d: (%d, %d)", p.Src, p.Dest, p.X, p.Y)
+}
+
+// Device handles the packets sent to a special address that does not
+// belong to any node.
+type Device interface {
+	// Receive handles a packet sent to the device. Returning true stops
+	// the network.
+	Receive(p Packet) bool
+	// Idle is called whenever the network goes idle and returns packets
+	// to inject into it. Returning true stops the network.
+	Idle() ([]Packet, bool)
+}
+
+type Stats struct {
+	Sent, Received int
+	IdlePolls      int
+}
+
+type Network struct {
+	sched   *intcode.Scheduler
+	pending [][]int64 // partially written packets
+	devices map[int64]Device
+	addrs   []int64 // device addresses, in attachment order
+	sent    bool
+	polls   int // nodes starved for input this round
+	stopped bool
+	err     error
+
+	Log   []Packet
+	Stats []Stats
+}
+
+var ErrIdle = errors.New("network idle")
+
+// New boots n copies of prog with addresses 0 through n-1.
+func New(prog []int64, n int) *Network {
+	vms := make([]*intcode.Machine, n)
+	for i := range vms {
+		vms[i] = intcode.NewMachine(prog)
+		vms[i].Push(int64(i))
+	}
+	net := &Network{
+		sched:   intcode.NewScheduler(vms...),
+		pending: make([][]int64, n),
+		devices: make(map[int64]Device),
+		Stats:   make([]Stats, n),
+	}
+	net.sched.Starved = func(i int) {
+		vms[i].Push(-1)
+		net.Stats[i].IdlePolls++
+		net.polls++
+	}
+	net.sched.Output = net.output
+	return net
+}
+
+// Randomize runs the nodes in a seeded random order instead of in
+// address order.
+func (net *Network) Randomize(seed int64) {
+	net.sched.Randomize(seed)
+}
+
+// Rounds returns the number of scheduler rounds run so far.
+func (net *Network) Rounds() int {
+	return net.sched.Rounds
+}
+
+// Attach handles packets sent to addr with the given device.
+func (net *Network) Attach(addr int64, d Device) {
+	if _, ok := net.devices[addr]; !ok {
+		net.addrs = append(net.addrs, addr)
+	}
+	net.devices[addr] = d
+}
+
+// Delivers a packet. Returns true if a device asked to stop.
+func (net *Network) send(p Packet) (bool, error) {
+	net.Log = append(net.Log, p)
+	net.sent = true
+	if p.Src >= 0 && p.Src < int64(len(net.Stats)) {
+		net.Stats[p.Src].Sent++
+	}
+	if p.Dest >= 0 && p.Dest < int64(len(net.Stats)) {
+		net.sched.Machines[p.Dest].Push(p.X, p.Y)
+		net.Stats[p.Dest].Received++
+		return false, nil
+	}
+	if d, ok := net.devices[p.Dest]; ok {
+		return d.Receive(p), nil
+	}
+	return false, fmt.Errorf("bad packet: %s", p)
+}
+
+func (net *Network) output(i int, out []int64) {
+	pending := append(net.pending[i], out...)
+	for ; len(pending) >= 3; pending = pending[3:] {
+		p := Packet{int64(i), pending[0], pending[1], pending[2]}
+		if stop, err := net.send(p); stop || err != nil {
+			net.err, net.stopped = err, true
+			net.sched.Stop()
+			break
+		}
+	}
+	net.pending[i] = pending
+}
+
+func (net *Network) running() int {
+	n := 0
+	for _, vm := range net.sched.Machines {
+		if vm.State() != intcode.Halted {
+			n++
+		}
+	}
+	return n
+}
+
+// Run simulates the network until a device asks it to stop. It returns
+// ErrIdle if the network goes idle and no device wakes it up, and the
+// error of any node that stops with one.
+func (net *Network) Run() error {
+	for {
+		net.sent, net.polls = false, 0
+		net.sched.Round()
+		if err := net.sched.Err(); err != nil {
+			return err
+		}
+		if net.stopped {
+			return net.err
+		}
+		running := net.running()
+		if running == 0 {
+			return fmt.Errorf("all nodes halted")
+		}
+		if net.sent || net.polls < running {
+			continue
+		}
+		injected := false
+		for _, addr := range net.addrs {
+			ps, stop := net.devices[addr].Idle()
+			for _, p := range ps {
+				p.Src = addr
+				injected = true
+				if stop, err := net.send(p); stop || err != nil {
+					return err
+				}
+			}
+			if stop {
+				return nil
+			}
+		}
+		if !injected {
+			return ErrIdle
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+)
+
+func TestDay23(t *testing.T) {
+	prog, err := intcode.ReadProgram("../day23/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	net := New(prog, 50)
+	nat := &NAT{}
+	net.Attach(255, nat)
+	if err := net.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if nat.First.Y != 22877 {
+		t.Errorf("first Y = %d, want 22877", nat.First.Y)
+	}
+	if !nat.Repeated || nat.Last.Y != 15210 {
+		t.Errorf("repeated Y = %d, %t, want 15210", nat.Last.Y, nat.Repeated)
+	}
+	sent := 0
+	for _, s := range net.Stats {
+		sent += s.Sent
+	}
+	if want := len(net.Log) - len(nat.Sent); sent != want {
+		t.Errorf("nodes sent %d packets, log has %d", sent, want)
+	}
+}
+
+func TestSink(t *testing.T) {
+	prog, err := intcode.ReadProgram("../day23/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	net := New(prog, 50)
+	sink := &Sink{}
+	net.Attach(255, sink)
+	if err := net.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !sink.Received || sink.Y != 22877 {
+		t.Errorf("sink got %v, %t, want Y = 22877", sink.Packet, sink.Received)
+	}
+}
+
+func TestNAT(t *testing.T) {
+	var nat NAT
+	if ps, stop := nat.Idle(); len(ps) != 0 || stop {
+		t.Errorf("empty NAT Idle() = %v, %t", ps, stop)
+	}
+	nat.Receive(Packet{Dest: 255, X: 1, Y: 2})
+	if ps, stop := nat.Idle(); len(ps) != 1 || ps[0].Y != 2 || stop {
+		t.Errorf("Idle() = %v, %t, want one packet with Y = 2", ps, stop)
+	}
+	nat.Receive(Packet{Dest: 255, X: 3, Y: 2})
+	if ps, stop := nat.Idle(); len(ps) != 0 || !stop || !nat.Repeated {
+		t.Errorf("Idle() = %v, %t, want stop on repeated Y", ps, stop)
+	}
+}
+
+func TestIdle(t *testing.T) {
+	// read forever without sending anything
+	net := New([]int64{3, 100, 1105, 1, 0}, 3)
+	if err := net.Run(); err != ErrIdle {
+		t.Errorf("Run() = %v, want %v", err, ErrIdle)
+	}
+	if net.Rounds() != 2 {
+		t.Errorf("Rounds() = %d, want 2", net.Rounds())
+	}
+}
+
+func TestBadDest(t *testing.T) {
+	// send (7, 1, 2) and halt
+	net := New([]int64{4, 7, 4, 8, 4, 9, 99, 7, 1, 2}, 1)
+	if err := net.Run(); err == nil {
+		t.Error("Run() succeeded sending to a missing address")
+	}
+}
+
+func TestBadNode(t *testing.T) {
+	net := New([]int64{77}, 2)
+	if err := net.Run(); !errors.Is(err, intcode.ErrBadOpcode) {
+		t.Errorf("Run() = %v, want %v", err, intcode.ErrBadOpcode)
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	prog, err := intcode.ReadProgram("../day23/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs [2][]Packet
+	for i := range logs {
+		net := New(prog, 50)
+		net.Randomize(2019)
+		nat := &NAT{}
+		net.Attach(255, nat)
+		if err := net.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if nat.Last.Y != 15210 {
+			t.Errorf("repeated Y = %d, want 15210", nat.Last.Y)
+		}
+		logs[i] = net.Log
+	}
+	if !reflect.DeepEqual(logs[0], logs[1]) {
+		t.Error("runs with the same seed produced different packet logs")
+	}
+}
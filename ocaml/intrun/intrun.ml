@@ -0,0 +1,23 @@
+(* Runs an intcode program, reading its input from stdin and writing its
+   output to stdout, one value per line. This is the runner used by the
+   conformance tests in ../../intcode. It uses day 9's machine, so that
+   the tests check the same interpreter as the solutions; build it with
+
+     ocamlopt -I ../day9 ../day9/vm.mli ../day9/vm.ml intrun.ml -o intrun *)
+
+include Vm
+
+let next_input () =
+  try Scanf.scanf " %d" (fun x -> x)
+  with End_of_file | Scanf.Scan_failure _ -> failwith "out of input"
+
+let () =
+  let path = Sys.argv.(1) in
+  let prog = Scanf.Scanning.open_in path |> read in
+  let rec loop vm =
+    let vm' = run vm in match vm_state vm' with
+    | Running -> loop vm'
+    | Halted -> ()
+    | Input -> vm_write (next_input ()) vm' |> loop
+    | Output -> vm_read vm' |> Printf.printf "%d\n"; loop vm'
+  in loop (vm_new prog)
@@ -0,0 +1,27 @@
+// Runs an intcode program, reading its input from stdin and writing its
+// output to stdout, one value per line. This is the runner used by the
+// conformance tests in ../../intcode.
+
+use std::env;
+use std::error;
+use std::fs;
+use std::io::{self, Read};
+
+fn main() -> Result<(), Box<dyn error::Error>> {
+    let path = env::args().nth(1).ok_or("usage: intrun file")?;
+    let text = fs::read_to_string(&path)?;
+    let prog = intcode::Program::new(&text)?;
+    let mut input = String::new();
+    io::stdin().read_to_string(&mut input)?;
+    let mut input = input.split_whitespace().map(|tok| tok.parse::<i64>());
+    let mut machine = intcode::Machine::new(&prog);
+    loop {
+        match machine.run()? {
+            intcode::State::Running => (),
+            intcode::State::Reading => machine.write(input.next().ok_or("out of input")??),
+            intcode::State::Writing => println!("{}", machine.read()),
+            intcode::State::Halted => break,
+        }
+    }
+    Ok(())
+}
@@ -0,0 +1,111 @@
+// Package search finds shortest paths through graphs given implicitly
+// by neighbor functions. States may be plain grid points or any other
+// comparable type, such as a point paired with a recursion depth.
+package search
+
+// Tree is a shortest-path tree rooted at Source, recording the distance
+// to and the predecessor of every state reached.
+type Tree[S comparable] struct {
+	Source S
+	Dist   map[S]int
+	Prev   map[S]S
+}
+
+func newTree[S comparable](src S) *Tree[S] {
+	return &Tree[S]{
+		Source: src,
+		Dist:   map[S]int{src: 0},
+		Prev:   make(map[S]S),
+	}
+}
+
+// Path returns the states along the shortest path from the source to
+// the given state, inclusive, or nil if the state wasn't reached.
+func (t *Tree[S]) Path(to S) []S {
+	if _, ok := t.Dist[to]; !ok {
+		return nil
+	}
+	path := []S{to}
+	for to != t.Source {
+		to = t.Prev[to]
+		path = append(path, to)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// BFS explores every state reachable from src.
+func BFS[S comparable](src S, nbrs func(S) []S) *Tree[S] {
+	t, _, _ := BFSTo(src, nbrs, nil)
+	return t
+}
+
+// BFSTo searches breadth-first from src until it reaches a state for
+// which goal returns true, and returns that state. If goal is nil, it
+// explores every reachable state.
+func BFSTo[S comparable](src S, nbrs func(S) []S, goal func(S) bool) (*Tree[S], S, bool) {
+	t := newTree(src)
+	for q := []S{src}; len(q) > 0; {
+		cur := q[0]
+		q = q[1:]
+		if goal != nil && goal(cur) {
+			return t, cur, true
+		}
+		for _, nbr := range nbrs(cur) {
+			if _, ok := t.Dist[nbr]; ok {
+				continue
+			}
+			t.Dist[nbr] = t.Dist[cur] + 1
+			t.Prev[nbr] = cur
+			q = append(q, nbr)
+		}
+	}
+	var zero S
+	return t, zero, false
+}
+
+// Bidirectional searches breadth-first from both src and dst at once
+// and returns the shortest path between them. The neighbor relation
+// must be symmetric.
+func Bidirectional[S comparable](src, dst S, nbrs func(S) []S) ([]S, bool) {
+	fwd, bwd := newTree(src), newTree(dst)
+	if src == dst {
+		return []S{src}, true
+	}
+	fq, bq := []S{src}, []S{dst}
+	for len(fq) > 0 && len(bq) > 0 {
+		// expand a whole level of the smaller frontier
+		t, other, q := fwd, bwd, &fq
+		if len(bq) < len(fq) {
+			t, other, q = bwd, fwd, &bq
+		}
+		var next []S
+		var meet S
+		found := false
+		for _, cur := range *q {
+			for _, nbr := range nbrs(cur) {
+				if _, ok := t.Dist[nbr]; ok {
+					continue
+				}
+				t.Dist[nbr] = t.Dist[cur] + 1
+				t.Prev[nbr] = cur
+				next = append(next, nbr)
+				if _, ok := other.Dist[nbr]; ok && (!found || t.Dist[nbr]+other.Dist[nbr] < t.Dist[meet]+other.Dist[meet]) {
+					meet, found = nbr, true
+				}
+			}
+		}
+		if found {
+			path := fwd.Path(meet)
+			back := bwd.Path(meet)
+			for i := len(back) - 2; i >= 0; i-- {
+				path = append(path, back[i])
+			}
+			return path, true
+		}
+		*q = next
+	}
+	return nil, false
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+const maze = `#########
+#S..#...#
+#.#.#.#.#
+#.#...#G#
+#########
+`
+
+func readMaze(t *testing.T) (func(geom.Pt2) []geom.Pt2, geom.Pt2, geom.Pt2) {
+	g, err := geom.ParseDense(strings.NewReader(maze), func(c rune) rune { return c })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src, dst geom.Pt2
+	g.Each(func(p geom.Pt2, c rune) {
+		switch c {
+		case 'S':
+			src = p
+		case 'G':
+			dst = p
+		}
+	})
+	nbrs := GridNeighbors(func(p geom.Pt2) bool {
+		c, ok := g.Get(p)
+		return ok && c != '#'
+	})
+	return nbrs, src, dst
+}
+
+func checkPath(t *testing.T, name string, path []geom.Pt2, src, dst geom.Pt2, want int) {
+	t.Helper()
+	if len(path) != want+1 || path[0] != src || path[len(path)-1] != dst {
+		t.Errorf("%s: path %v, want %d steps from %v to %v", name, path, want, src, dst)
+		return
+	}
+	for i := 1; i < len(path); i++ {
+		if path[i].ManhattanDist(path[i-1]) != 1 {
+			t.Errorf("%s: path %v jumps from %v to %v", name, path, path[i-1], path[i])
+		}
+	}
+}
+
+func TestGridSearches(t *testing.T) {
+	nbrs, src, dst := readMaze(t)
+	const want = 12
+
+	tree := BFS(src, nbrs)
+	if d := tree.Dist[dst]; d != want {
+		t.Errorf("BFS distance = %d, want %d", d, want)
+	}
+	checkPath(t, "BFS", tree.Path(dst), src, dst, want)
+
+	tree, found, ok := BFSTo(src, nbrs, func(p geom.Pt2) bool { return p == dst })
+	if !ok || found != dst {
+		t.Errorf("BFSTo found %v, %t", found, ok)
+	}
+	checkPath(t, "BFSTo", tree.Path(dst), src, dst, want)
+
+	tree = Dijkstra(src, Unweighted(nbrs))
+	checkPath(t, "Dijkstra", tree.Path(dst), src, dst, want)
+
+	tree, _, ok = AStar(src, Unweighted(nbrs), func(p geom.Pt2) bool { return p == dst }, Manhattan(dst))
+	if !ok {
+		t.Error("AStar found no path")
+	}
+	checkPath(t, "AStar", tree.Path(dst), src, dst, want)
+
+	path, ok := Bidirectional(src, dst, nbrs)
+	if !ok {
+		t.Error("Bidirectional found no path")
+	}
+	checkPath(t, "Bidirectional", path, src, dst, want)
+}
+
+func TestUnreachable(t *testing.T) {
+	nbrs, src, _ := readMaze(t)
+	wall := geom.Zero2
+	if path := BFS(src, nbrs).Path(wall); path != nil {
+		t.Errorf("BFS path to wall = %v", path)
+	}
+	if _, ok := Bidirectional(src, wall, nbrs); ok {
+		t.Error("Bidirectional found a path to a wall")
+	}
+	if _, _, ok := DijkstraTo(src, Unweighted(nbrs), func(p geom.Pt2) bool { return p == wall }); ok {
+		t.Error("DijkstraTo found a path to a wall")
+	}
+}
+
+// a state combining a position on a line with a number of teleports used
+type state struct {
+	pos, jumps int
+}
+
+func TestCompoundState(t *testing.T) {
+	// walk along 0..20 one step at a time at cost 3, or teleport to
+	// double the position at cost 5, at most twice
+	edges := func(s state) []Edge[state] {
+		var es []Edge[state]
+		if s.pos < 20 {
+			es = append(es, Edge[state]{state{s.pos + 1, s.jumps}, 3})
+		}
+		if s.jumps < 2 && 2*s.pos <= 20 {
+			es = append(es, Edge[state]{state{2 * s.pos, s.jumps + 1}, 5})
+		}
+		return es
+	}
+	tree, found, ok := DijkstraTo(state{1, 0}, edges, func(s state) bool { return s.pos == 20 })
+	if !ok {
+		t.Fatal("DijkstraTo found no path")
+	}
+	// 1 -> 5 (12), 10 (5), 20 (5)
+	if d := tree.Dist[found]; d != 22 {
+		t.Errorf("cost = %d, want 22", d)
+	}
+	want := []state{{1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {10, 1}, {20, 2}}
+	if path := tree.Path(found); !reflect.DeepEqual(path, want) {
+		t.Errorf("path = %v, want %v", path, want)
+	}
+}
+
+func TestInconsistentHeuristic(t *testing.T) {
+	// s-a-c-g costs 5, but h(a) is high enough that c is first reached
+	// through b, at cost 4
+	graph := map[string][]Edge[string]{
+		"s": {{"a", 1}, {"b", 1}},
+		"a": {{"c", 1}},
+		"b": {{"c", 3}},
+		"c": {{"g", 3}},
+	}
+	edges := func(s string) []Edge[string] { return graph[s] }
+	h := func(s string) int {
+		if s == "a" {
+			return 4 // admissible, but more than 1 + h(c)
+		}
+		return 0
+	}
+	tree, found, ok := AStar("s", edges, func(s string) bool { return s == "g" }, h)
+	if !ok {
+		t.Fatal("AStar found no path")
+	}
+	if d := tree.Dist[found]; d != 5 {
+		t.Errorf("cost = %d, want 5", d)
+	}
+	want := []string{"s", "a", "c", "g"}
+	if path := tree.Path(found); !reflect.DeepEqual(path, want) {
+		t.Errorf("path = %v, want %v", path, want)
+	}
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"container/heap"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// Unweighted gives every neighbor an edge of cost 1.
+func Unweighted[S comparable](nbrs func(S) []S) func(S) []Edge[S] {
+	return func(s S) []Edge[S] {
+		var edges []Edge[S]
+		for _, nbr := range nbrs(s) {
+			edges = append(edges, Edge[S]{nbr, 1})
+		}
+		return edges
+	}
+}
+
+type item[S comparable] struct {
+	s        S
+	dist     int // the cost to s when it was pushed
+	priority int
+}
+
+type queue[S comparable] []item[S]
+
+func (q queue[S]) Len() int            { return len(q) }
+func (q queue[S]) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q queue[S]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue[S]) Push(x interface{}) { *q = append(*q, x.(item[S])) }
+
+func (q *queue[S]) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
+
+// Dijkstra finds the cheapest path to every state reachable from src.
+// Edge costs must not be negative.
+func Dijkstra[S comparable](src S, edges func(S) []Edge[S]) *Tree[S] {
+	t, _, _ := AStar(src, edges, nil, nil)
+	return t
+}
+
+// DijkstraTo is like Dijkstra, but stops at the first state for which
+// goal returns true, which is then the cheapest such state to reach.
+func DijkstraTo[S comparable](src S, edges func(S) []Edge[S], goal func(S) bool) (*Tree[S], S, bool) {
+	return AStar(src, edges, goal, nil)
+}
+
+// AStar is like DijkstraTo, but visits states in order of their cost
+// plus the estimated remaining cost h. If h never overestimates, the
+// state found is still the cheapest to reach; if h is also consistent,
+// never dropping by more than an edge's cost along it, no state is
+// visited twice. A nil h estimates zero.
+func AStar[S comparable](src S, edges func(S) []Edge[S], goal func(S) bool, h func(S) int) (*Tree[S], S, bool) {
+	if h == nil {
+		h = func(S) int { return 0 }
+	}
+	// tentative costs and predecessors, copied into t as states are
+	// popped off the queue. A state is visited again if a cheaper path
+	// to it turns up later, which only happens if h isn't consistent.
+	t := newTree(src)
+	dist, prev := map[S]int{src: 0}, make(map[S]S)
+	q := &queue[S]{{src, 0, h(src)}}
+	for q.Len() > 0 {
+		it := heap.Pop(q).(item[S])
+		cur := it.s
+		if it.dist != dist[cur] {
+			continue // a cheaper path was pushed since
+		}
+		t.Dist[cur] = dist[cur]
+		if cur != src {
+			t.Prev[cur] = prev[cur]
+		}
+		if goal != nil && goal(cur) {
+			return t, cur, true
+		}
+		for _, e := range edges(cur) {
+			d := dist[cur] + e.Cost
+			if old, ok := dist[e.To]; ok && old <= d {
+				continue
+			}
+			dist[e.To] = d
+			prev[e.To] = cur
+			heap.Push(q, item[S]{e.To, d, d + h(e.To)})
+		}
+	}
+	var zero S
+	return t, zero, false
+}
+
+// Manhattan returns an A* heuristic estimating the distance to goal on
+// a grid with orthogonal moves of cost at least 1.
+func Manhattan(goal geom.Pt2) func(geom.Pt2) int {
+	return func(p geom.Pt2) int {
+		return p.ManhattanDist(goal)
+	}
+}
+
+// GridNeighbors returns a neighbor function that moves orthogonally
+// between the points for which open returns true.
+func GridNeighbors(open func(geom.Pt2) bool) func(geom.Pt2) []geom.Pt2 {
+	return func(p geom.Pt2) []geom.Pt2 {
+		var nbrs []geom.Pt2
+		for _, nbr := range p.ManhattanNeighbors() {
+			if open(nbr) {
+				nbrs = append(nbrs, nbr)
+			}
+		}
+		return nbrs
+	}
+}
@@ -0,0 +1,321 @@
+package viewer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/gdamore/tcell"
+)
+
+// Tile says how to draw a cell.
+type Tile struct {
+	Rune  rune
+	Style tcell.Style
+}
+
+// Config describes how to draw a program's world and how to play it.
+type Config struct {
+	Decoder Decoder
+
+	// Cells with values that aren't here are drawn as themselves if
+	// they're printable ASCII characters, or '?' otherwise.
+	Tiles map[int64]Tile
+
+	// Input sent to the program when a key is pressed. Keys is for keys
+	// like the arrows, and Runes for keys that type a character, which
+	// take the place of the viewer's own keys.
+	Keys  map[tcell.Key]int64
+	Runes map[rune]int64
+
+	// Input sent when the program reads, no key has been pressed since
+	// it last read and the decoder, if it's an Inputter, has nothing to
+	// send. If it's empty, the program waits for a key.
+	Idle []int64
+
+	Speed int // instructions run per frame at first, or 1
+	FPS   int // frames drawn per second, or 30
+}
+
+// The viewer's own keys.
+const help = "space pause  . step  <> speed  +- zoom  hjkl pan  c center  q quit"
+
+const maxSpeed = 1 << 20
+
+// Zoom levels: positive levels draw each cell as a square of that many
+// screen cells, and negative ones draw a square of that many cells as
+// one screen cell.
+var zooms = []int{-4, -3, -2, 1, 2, 3, 4}
+
+const unzoomed = 3
+
+// Viewer runs a program and draws its world.
+type Viewer struct {
+	screen tcell.Screen
+	vm     *intcode.Machine
+	cfg    Config
+	world  *World
+	out    []int64 // not yet decoded
+	input  []int64 // sent the next time the program reads
+	paused bool
+	speed  int
+	zoom   int      // index into zooms
+	offset geom.Pt2 // panned from the world's top left, in cells
+}
+
+// New returns a viewer that draws on a screen that has already been
+// initialized.
+func New(screen tcell.Screen, vm *intcode.Machine, cfg Config) *Viewer {
+	if cfg.Speed < 1 {
+		cfg.Speed = 1
+	}
+	if cfg.FPS < 1 {
+		cfg.FPS = 30
+	}
+	return &Viewer{
+		screen: screen,
+		vm:     vm,
+		cfg:    cfg,
+		world:  NewWorld(),
+		speed:  cfg.Speed,
+		zoom:   unzoomed,
+	}
+}
+
+// World returns what the program has drawn so far.
+func (v *Viewer) World() *World {
+	return v.world
+}
+
+func (v *Viewer) decode() {
+	out := append(v.out, v.vm.Outputs()...)
+	v.out = v.cfg.Decoder.Decode(v.world, out)
+}
+
+// Gives a blocked program its input, returning false if there isn't any.
+func (v *Viewer) feed() bool {
+	if len(v.input) > 0 {
+		v.vm.Push(v.input...)
+		v.input = nil
+		return true
+	}
+	if in, ok := v.cfg.Decoder.(Inputter); ok {
+		v.decode()
+		if vals := in.Input(v.world); len(vals) > 0 {
+			v.vm.Push(vals...)
+			return true
+		}
+	}
+	if len(v.cfg.Idle) > 0 {
+		v.vm.Push(v.cfg.Idle...)
+		return true
+	}
+	return false
+}
+
+// Runs up to n instructions, stopping early if the program halts or
+// waits for a key.
+func (v *Viewer) step(n int) {
+	defer v.decode()
+	for i := 0; i < n; {
+		switch v.vm.Step() {
+		case intcode.Halted:
+			return
+		case intcode.Blocked:
+			// the read didn't run, so try it again with input
+			if !v.feed() {
+				return
+			}
+		default:
+			i++
+		}
+	}
+}
+
+// Finds the cell to draw at screen position (sx, sy). When zoomed out,
+// that's the first cell in the square with a nonzero value, or failing
+// that the first one that's set.
+func (v *Viewer) cellAt(sx, sy int) (int64, bool) {
+	origin := v.world.Cells.Bounds().Lo.Add(v.offset)
+	z := zooms[v.zoom]
+	if z > 0 {
+		return v.world.Cells.Get(origin.Add(geom.Pt2{X: sx / z, Y: sy / z}))
+	}
+	k := -z
+	var found int64
+	ok := false
+	for dy := 0; dy < k; dy++ {
+		for dx := 0; dx < k; dx++ {
+			p := origin.Add(geom.Pt2{X: sx*k + dx, Y: sy*k + dy})
+			if c, set := v.world.Cells.Get(p); set && (!ok || found == 0) {
+				found, ok = c, true
+			}
+		}
+	}
+	return found, ok
+}
+
+func (v *Viewer) tile(c int64) Tile {
+	if t, ok := v.cfg.Tiles[c]; ok {
+		return t
+	}
+	if c >= ' ' && c <= '~' {
+		return Tile{Rune: rune(c)}
+	}
+	return Tile{Rune: '?'}
+}
+
+func (v *Viewer) status() string {
+	state := v.vm.State().String()
+	if v.paused && v.vm.State() != intcode.Halted {
+		state = "paused"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  pc %d  rb %d  steps %d  in %d  speed %d", state,
+		v.vm.PC(), v.vm.RelBase(), v.vm.Steps(), v.vm.Queued(), v.speed)
+	if z := zooms[v.zoom]; z < 0 {
+		fmt.Fprintf(&b, "  zoom 1/%d", -z)
+	} else if z > 1 {
+		fmt.Fprintf(&b, "  zoom %dx", z)
+	}
+	for _, name := range v.world.LabelNames() {
+		fmt.Fprintf(&b, "  %s %d", name, v.world.Labels[name])
+	}
+	if err := v.vm.Err(); err != nil {
+		fmt.Fprintf(&b, "  error: %s", err)
+	}
+	return b.String()
+}
+
+func (v *Viewer) draw() {
+	w, h := v.screen.Size()
+	v.screen.Clear()
+	for sy := 0; sy < h-1; sy++ {
+		for sx := 0; sx < w; sx++ {
+			if c, ok := v.cellAt(sx, sy); ok {
+				t := v.tile(c)
+				v.screen.SetContent(sx, sy, t.Rune, nil, t.Style)
+			}
+		}
+	}
+	bar := v.status()
+	if len(bar)+2+len(help) <= w {
+		bar += strings.Repeat(" ", w-len(bar)-len(help)) + help
+	}
+	style := tcell.StyleDefault.Reverse(true)
+	for sx := 0; sx < w; sx++ {
+		r := ' '
+		if sx < len(bar) {
+			r = rune(bar[sx])
+		}
+		v.screen.SetContent(sx, h-1, r, nil, style)
+	}
+	v.screen.Show()
+}
+
+// Moves the view by a quarter of the screen in the given direction.
+func (v *Viewer) pan(dx, dy int) {
+	w, h := v.screen.Size()
+	if z := zooms[v.zoom]; z > 0 {
+		w, h = w/z, h/z
+	} else {
+		w, h = w*-z, h*-z
+	}
+	v.offset = v.offset.Add(geom.Pt2{X: dx * (w/4 + 1), Y: dy * (h/4 + 1)})
+}
+
+// Handles a key, returning true if it's time to quit.
+func (v *Viewer) key(e *tcell.EventKey) bool {
+	if e.Key() != tcell.KeyRune {
+		if in, ok := v.cfg.Keys[e.Key()]; ok {
+			v.input = []int64{in}
+		}
+		return e.Key() == tcell.KeyCtrlC || e.Key() == tcell.KeyEscape
+	}
+	if in, ok := v.cfg.Runes[e.Rune()]; ok {
+		v.input = []int64{in}
+		return false
+	}
+	switch e.Rune() {
+	case 'q':
+		return true
+	case ' ':
+		v.paused = !v.paused
+	case '.':
+		v.paused = true
+		v.step(1)
+	case '>':
+		if v.speed < maxSpeed {
+			v.speed *= 2
+		}
+	case '<':
+		if v.speed > 1 {
+			v.speed /= 2
+		}
+	case '+', '=':
+		if v.zoom < len(zooms)-1 {
+			v.zoom++
+		}
+	case '-':
+		if v.zoom > 0 {
+			v.zoom--
+		}
+	case 'h':
+		v.pan(-1, 0)
+	case 'l':
+		v.pan(1, 0)
+	case 'k':
+		v.pan(0, -1)
+	case 'j':
+		v.pan(0, 1)
+	case 'c':
+		v.offset, v.zoom = geom.Zero2, unzoomed
+	}
+	return false
+}
+
+// Run runs the program and draws its world until the q, escape or
+// control-C key is pressed. The program keeps being drawn after it
+// halts; its error, if it stopped with one, is shown in the status bar
+// and can be found with the machine's Err method.
+func (v *Viewer) Run() {
+	events := make(chan tcell.Event)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			ev := v.screen.PollEvent()
+			if ev == nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+	tick := time.NewTicker(time.Second / time.Duration(v.cfg.FPS))
+	defer tick.Stop()
+	v.draw()
+	for {
+		select {
+		case ev := <-events:
+			switch e := ev.(type) {
+			case *tcell.EventKey:
+				if v.key(e) {
+					return
+				}
+			case *tcell.EventResize:
+				v.screen.Sync()
+			}
+		case <-tick.C:
+			if !v.paused && v.vm.State() != intcode.Halted {
+				v.step(v.speed)
+			}
+		}
+		v.draw()
+	}
+}
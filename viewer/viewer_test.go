@@ -0,0 +1,202 @@
+package viewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+	"github.com/dhconnelly/advent-of-code-2019/intcode"
+	"github.com/gdamore/tcell"
+)
+
+func TestTriples(t *testing.T) {
+	w := NewWorld()
+	d := Triples{Labels: map[geom.Pt2]string{{X: -1, Y: 0}: "score"}}
+	rest := d.Decode(w, []int64{1, 2, 3, -1, 0, 42, 5, 6})
+	if len(rest) != 2 || rest[0] != 5 || rest[1] != 6 {
+		t.Errorf("left over %v, want [5 6]", rest)
+	}
+	if v, ok := w.Cells.Get(geom.Pt2{X: 1, Y: 2}); !ok || v != 3 {
+		t.Errorf("(1, 2) = %d, %t", v, ok)
+	}
+	if _, ok := w.Cells.Get(geom.Pt2{X: -1, Y: 0}); ok {
+		t.Error("label was drawn")
+	}
+	if w.Labels["score"] != 42 {
+		t.Errorf("score = %d, want 42", w.Labels["score"])
+	}
+}
+
+func ascii(s string) []int64 {
+	var out []int64
+	for _, c := range s {
+		out = append(out, int64(c))
+	}
+	return out
+}
+
+func TestASCII(t *testing.T) {
+	w := NewWorld()
+	d := &ASCII{}
+	d.Decode(w, ascii("#.\n.#"))
+	if v, _ := w.Cells.Get(geom.Pt2{X: 1, Y: 1}); v != '#' {
+		t.Errorf("first frame isn't drawn as it comes")
+	}
+	d.Decode(w, ascii("\n\n..\n"))
+	if v, _ := w.Cells.Get(geom.Pt2{X: 0, Y: 0}); v != '#' {
+		t.Errorf("second frame was drawn before it was complete")
+	}
+	d.Decode(w, append(ascii("..\n\n"), 1234))
+	if v, _ := w.Cells.Get(geom.Pt2{X: 0, Y: 0}); v != '.' {
+		t.Errorf("second frame wasn't drawn")
+	}
+	if w.Labels["output"] != 1234 {
+		t.Errorf("output = %d, want 1234", w.Labels["output"])
+	}
+}
+
+// Reads a value and draws it at (0, 0), forever.
+var echo = []int64{3, 100, 104, 0, 104, 0, 4, 100, 1105, 1, 0}
+
+func newTestViewer(t *testing.T, cfg Config) (*Viewer, tcell.SimulationScreen) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(screen.Fini)
+	screen.SetSize(80, 5)
+	return New(screen, intcode.NewMachine(echo), cfg), screen
+}
+
+func contents(screen tcell.SimulationScreen) []string {
+	cells, w, h := screen.GetContents()
+	var rows []string
+	for y := 0; y < h; y++ {
+		var b strings.Builder
+		for x := 0; x < w; x++ {
+			if rs := cells[y*w+x].Runes; len(rs) > 0 {
+				b.WriteRune(rs[0])
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
+
+func TestViewer(t *testing.T) {
+	v, screen := newTestViewer(t, Config{
+		Decoder: Triples{},
+		Tiles:   map[int64]Tile{1: {Rune: '#'}},
+		Runes:   map[rune]int64{'a': 1},
+		Keys:    map[tcell.Key]int64{tcell.KeyLeft: 'L'},
+	})
+	v.step(100)
+	if v.vm.State() != intcode.Blocked || v.vm.Steps() != 0 {
+		t.Fatalf("ran %d steps without input", v.vm.Steps())
+	}
+
+	v.key(tcell.NewEventKey(tcell.KeyRune, 'a', 0))
+	v.step(100)
+	v.draw()
+	rows := contents(screen)
+	if rows[0][0] != '#' {
+		t.Errorf("drew %q, want a tile", rows[0][0])
+	}
+	bar := rows[len(rows)-1]
+	if !strings.HasPrefix(bar, "blocked  pc 0  rb 0  steps 5  in 0  speed 1") {
+		t.Errorf("status bar: %q", bar)
+	}
+
+	v.key(tcell.NewEventKey(tcell.KeyLeft, 0, 0))
+	v.step(100)
+	v.draw()
+	if rows := contents(screen); rows[0][0] != 'L' {
+		t.Errorf("drew %q, want 'L'", rows[0][0])
+	}
+}
+
+func TestViewerKeys(t *testing.T) {
+	v, screen := newTestViewer(t, Config{Decoder: Triples{}, Idle: []int64{'x'}})
+	v.key(tcell.NewEventKey(tcell.KeyRune, '.', 0))
+	if !v.paused || v.vm.Steps() != 1 {
+		t.Errorf("single step: paused %t after %d steps", v.paused, v.vm.Steps())
+	}
+	v.key(tcell.NewEventKey(tcell.KeyRune, ' ', 0))
+	if v.paused {
+		t.Error("space didn't unpause")
+	}
+	v.key(tcell.NewEventKey(tcell.KeyRune, '>', 0))
+	v.key(tcell.NewEventKey(tcell.KeyRune, '>', 0))
+	v.key(tcell.NewEventKey(tcell.KeyRune, '<', 0))
+	if v.speed != 2 {
+		t.Errorf("speed = %d, want 2", v.speed)
+	}
+
+	// idle input keeps the program going
+	v.step(20)
+	v.key(tcell.NewEventKey(tcell.KeyRune, '+', 0))
+	v.draw()
+	rows := contents(screen)
+	if !strings.HasPrefix(rows[0], "xx ") || !strings.HasPrefix(rows[1], "xx ") {
+		t.Errorf("zoomed in: got %q", rows[:2])
+	}
+	if !strings.Contains(rows[len(rows)-1], "zoom 2x") {
+		t.Errorf("status bar: %q", rows[len(rows)-1])
+	}
+
+	v.key(tcell.NewEventKey(tcell.KeyRune, 'l', 0))
+	v.draw()
+	if rows := contents(screen); rows[0][0] != ' ' {
+		t.Errorf("panned right: got %q", rows[0])
+	}
+	v.key(tcell.NewEventKey(tcell.KeyRune, 'c', 0))
+	if v.offset != geom.Zero2 || zooms[v.zoom] != 1 {
+		t.Errorf("c left offset %v and zoom %d", v.offset, zooms[v.zoom])
+	}
+
+	for _, e := range []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'q', 0),
+		tcell.NewEventKey(tcell.KeyEscape, 0, 0),
+		tcell.NewEventKey(tcell.KeyCtrlC, 0, 0),
+	} {
+		if !v.key(e) {
+			t.Errorf("%s didn't quit", e.Name())
+		}
+	}
+}
+
+// Sends one more than the value at (0, 0) each time, checking that the
+// world shows the last value sent.
+type counter struct {
+	Triples
+	sent []int64
+	t    *testing.T
+}
+
+func (c *counter) Input(w *World) []int64 {
+	if len(c.sent) == 3 {
+		return nil
+	}
+	v, ok := w.Cells.Get(geom.Zero2)
+	if n := len(c.sent); n > 0 && (!ok || v != c.sent[n-1]) {
+		c.t.Errorf("world shows %d, %t before sending input %d", v, ok, n)
+	}
+	c.sent = append(c.sent, v+1)
+	return []int64{v + 1}
+}
+
+func TestInputter(t *testing.T) {
+	c := &counter{t: t}
+	v, _ := newTestViewer(t, Config{Decoder: c, Idle: []int64{100}})
+	v.step(21)
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(c.sent, want) {
+		t.Errorf("sent %v, want %v", c.sent, want)
+	}
+	if got := v.World().Cells.At(geom.Zero2); got != 100 {
+		t.Errorf("(0, 0) = %d, want the idle input once the inputter stops", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Package viewer shows the world that an intcode program draws, live in
+// the terminal, while it runs.
+package viewer
+
+import (
+	"sort"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+// World is what a program has drawn so far. Cells are in screen
+// coordinates, with y growing downward.
+type World struct {
+	Cells  *geom.Grid[int64]
+	Labels map[string]int64 // values shown in the status bar, like a score
+}
+
+func NewWorld() *World {
+	return &World{Cells: geom.NewSparse[int64](), Labels: make(map[string]int64)}
+}
+
+// LabelNames returns the names of the labels in order.
+func (w *World) LabelNames() []string {
+	var names []string
+	for name := range w.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A Decoder turns a program's output into changes to its world.
+type Decoder interface {
+	// Decode uses as many of the outputs as it can and returns the rest,
+	// which are passed again at the front of the next outputs.
+	Decode(w *World, out []int64) []int64
+}
+
+// An Inputter is a Decoder that also decides what the program reads,
+// for programs that have to be told about the world they've drawn.
+type Inputter interface {
+	Decoder
+
+	// Input returns the values to send when the program is waiting for
+	// input and no key has been pressed, or nil to wait for a key or send
+	// Config.Idle. The world is up to date with the program's output.
+	Input(w *World) []int64
+}
+
+// Triples decodes outputs in threes as x, y and the value of the cell at
+// (x, y). Values written to the positions in Labels aren't drawn, but are
+// shown in the status bar under the given names instead.
+type Triples struct {
+	Labels map[geom.Pt2]string
+}
+
+func (t Triples) Decode(w *World, out []int64) []int64 {
+	for ; len(out) >= 3; out = out[3:] {
+		p := geom.Pt2{X: int(out[0]), Y: int(out[1])}
+		if name, ok := t.Labels[p]; ok {
+			w.Labels[name] = out[2]
+		} else {
+			w.Cells.Set(p, out[2])
+		}
+	}
+	return out
+}
+
+// ASCII decodes outputs as characters drawing frames of text, one row
+// per line, with a blank line after each frame. Each frame replaces the
+// last one once it's complete, except that the first is shown as it's
+// drawn. Values too big to be ASCII are shown as the "output" label.
+type ASCII struct {
+	frame  *geom.Grid[int64]
+	pos    geom.Pt2
+	frames int
+}
+
+func (a *ASCII) Decode(w *World, out []int64) []int64 {
+	if a.frame == nil {
+		a.frame = geom.NewSparse[int64]()
+	}
+	for _, v := range out {
+		switch {
+		case v > 127 || v < 0:
+			w.Labels["output"] = v
+		case v == '\n' && a.pos.X == 0 && a.pos.Y > 0:
+			// a blank line ends the frame
+			w.Cells = a.frame
+			a.frame, a.pos = geom.NewSparse[int64](), geom.Zero2
+			a.frames++
+		case v == '\n':
+			a.pos = geom.Pt2{X: 0, Y: a.pos.Y + 1}
+		default:
+			a.frame.Set(a.pos, v)
+			a.pos.X++
+		}
+	}
+	if a.frames == 0 {
+		w.Cells = a.frame
+	}
+	return nil
+}
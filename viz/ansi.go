@@ -0,0 +1,61 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+// Replaced in tests.
+var sleep = time.Sleep
+
+// Draws each cell as two spaces with the cell's color as the background,
+// since terminal cells are about twice as tall as they are wide.
+func (r *Recorder) writeFrame(w *bufio.Writer, b geom.Rect, f *Frame) {
+	fmt.Fprint(w, "\x1b[H")
+	for y := b.Lo.Y; y <= b.Hi.Y; y++ {
+		for x := b.Lo.X; x <= b.Hi.X; x++ {
+			cr, cg, cb, _ := r.Palette[f.At(geom.Pt2{X: x, Y: y})].RGBA()
+			fmt.Fprintf(w, "\x1b[48;2;%d;%d;%dm  ", cr>>8, cg>>8, cb>>8)
+		}
+		fmt.Fprint(w, "\x1b[0m\n")
+	}
+}
+
+func (r *Recorder) writeANSI(w io.Writer, delay time.Duration) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	b := r.bounds()
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "\x1b[2J")
+	for i, f := range r.Frames() {
+		if i > 0 && delay > 0 {
+			sleep(delay)
+		}
+		r.writeFrame(bw, b, f)
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteANSI writes the frames one after another as ANSI escape codes
+// that clear the terminal and draw each frame over the last, so that
+// printing them shows the last frame.
+func (r *Recorder) WriteANSI(w io.Writer) error {
+	return r.writeANSI(w, 0)
+}
+
+// Play is like WriteANSI, but waits between frames to play them at the
+// recorder's frame rate.
+func (r *Recorder) Play(w io.Writer) error {
+	if r.FPS < 1 {
+		return fmt.Errorf("bad frame rate: %d", r.FPS)
+	}
+	return r.writeANSI(w, time.Second/time.Duration(r.FPS))
+}
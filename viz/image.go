@@ -0,0 +1,143 @@
+package viz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image/gif"
+	"image/png"
+	"io"
+)
+
+// WriteGIF writes the frames as an animated GIF that loops forever.
+func (r *Recorder) WriteGIF(w io.Writer) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	// GIF delays are in hundredths of a second
+	delay := 100 / r.FPS
+	if delay < 2 {
+		delay = 2 // browsers slow down anything faster
+	}
+	g := &gif.GIF{Image: r.images()}
+	for range g.Image {
+		g.Delay = append(g.Delay, delay)
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// WritePNG writes the last frame as a PNG.
+func (r *Recorder) WritePNG(w io.Writer) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	imgs := r.images()
+	return png.Encode(w, imgs[len(imgs)-1])
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+type chunk struct {
+	typ  string
+	data []byte
+}
+
+func readChunks(b []byte) ([]chunk, error) {
+	if !bytes.HasPrefix(b, pngHeader) {
+		return nil, fmt.Errorf("not a PNG")
+	}
+	b = b[len(pngHeader):]
+	var chunks []chunk
+	for len(b) >= 12 {
+		n := int(binary.BigEndian.Uint32(b))
+		if len(b) < 12+n {
+			break
+		}
+		chunks = append(chunks, chunk{string(b[4:8]), b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	if len(b) != 0 {
+		return nil, fmt.Errorf("truncated PNG chunk")
+	}
+	return chunks, nil
+}
+
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(typ)
+	buf.Write(data)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()[4:]))
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteAPNG writes the frames as an animated PNG that loops forever.
+// Viewers that don't understand APNG show the first frame.
+//
+// Each frame is encoded as a PNG of its own, and then its image data is
+// moved into the animation: the first frame's IDAT chunks are kept as
+// they are, and the rest become fdAT chunks. Every frame has the same
+// size and palette, so the first frame's header chunks serve for all of
+// them.
+func (r *Recorder) WriteAPNG(w io.Writer) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	imgs := r.images()
+	var out bytes.Buffer
+	out.Write(pngHeader)
+	var seq uint32
+	for i, img := range imgs {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		chunks, err := readChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			for _, c := range chunks {
+				if c.typ == "IDAT" {
+					break
+				}
+				writeChunk(&out, c.typ, c.data)
+				if c.typ == "IHDR" {
+					// frame count, then zero plays to loop forever
+					actl := make([]byte, 8)
+					binary.BigEndian.PutUint32(actl, uint32(len(imgs)))
+					writeChunk(&out, "acTL", actl)
+				}
+			}
+		}
+
+		// sequence number, size, offset, delay as a fraction of a
+		// second, and then zero to neither dispose nor blend
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(img.Rect.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(img.Rect.Dy()))
+		binary.BigEndian.PutUint16(fctl[20:], 1)
+		binary.BigEndian.PutUint16(fctl[22:], uint16(r.FPS))
+		writeChunk(&out, "fcTL", fctl)
+		seq++
+
+		for _, c := range chunks {
+			switch {
+			case c.typ != "IDAT":
+			case i == 0:
+				writeChunk(&out, c.typ, c.data)
+			default:
+				fdat := make([]byte, 4, 4+len(c.data))
+				binary.BigEndian.PutUint32(fdat, seq)
+				writeChunk(&out, "fdAT", append(fdat, c.data...))
+				seq++
+			}
+		}
+	}
+	writeChunk(&out, "IEND", nil)
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,217 @@
+// Package viz records grids of cells as frames of an animation and
+// writes them as an animated GIF or APNG, a PNG of the final frame, or
+// ANSI text for playing back in a terminal.
+package viz
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+// Frame is a rectangle of cells, each an index into the recorder's
+// palette. Bounds are in screen coordinates, with y growing downward,
+// and the cells are stored row by row from the top.
+type Frame struct {
+	Bounds geom.Rect
+	Cells  []uint8
+}
+
+// NewFrame returns a frame with its top left cell at (0, 0) and every
+// cell set to 0.
+func NewFrame(width, height int) *Frame {
+	r := geom.Rect{Hi: geom.Pt2{X: width - 1, Y: height - 1}}
+	return &Frame{Bounds: r, Cells: make([]uint8, width*height)}
+}
+
+// Set sets the cell at p, which must be within the frame's bounds.
+func (f *Frame) Set(p geom.Pt2, c uint8) {
+	w := f.Bounds.Width()
+	f.Cells[(p.Y-f.Bounds.Lo.Y)*w+p.X-f.Bounds.Lo.X] = c
+}
+
+// At returns the cell at p, or 0 if p is outside the frame.
+func (f *Frame) At(p geom.Pt2) uint8 {
+	if !f.Bounds.Contains(p) {
+		return 0
+	}
+	w := f.Bounds.Width()
+	return f.Cells[(p.Y-f.Bounds.Lo.Y)*w+p.X-f.Bounds.Lo.X]
+}
+
+// Snapshot draws the grid's bounds as a frame, using f to pick the
+// palette index of each cell. For points without a cell, f is passed
+// false. Cartesian grids are flipped so that their highest row is at the
+// top.
+func Snapshot[T any](g *geom.Grid[T], sys geom.System, f func(v T, ok bool) uint8) *Frame {
+	b := g.Bounds()
+	if sys == geom.Cartesian {
+		b = geom.Rect{
+			Lo: geom.Pt2{X: b.Lo.X, Y: -b.Hi.Y},
+			Hi: geom.Pt2{X: b.Hi.X, Y: -b.Lo.Y},
+		}
+	}
+	fr := &Frame{Bounds: b, Cells: make([]uint8, b.Width()*b.Height())}
+	i := 0
+	for _, y := range sys.Rows(g.Bounds()) {
+		for x := b.Lo.X; x <= b.Hi.X; x++ {
+			fr.Cells[i] = f(g.Get(geom.Pt2{X: x, Y: y}))
+			i++
+		}
+	}
+	return fr
+}
+
+// Recorder collects frames. The zero value isn't usable; call
+// NewRecorder.
+type Recorder struct {
+	Palette color.Palette // cell values index into this
+	Scale   int           // pixels along each side of a cell in images
+	FPS     int           // frames per second when played back
+	Every   int           // keep only every nth frame added, and the last
+
+	frames  []*Frame
+	added   int
+	pending *Frame // the last frame added, if it wasn't kept
+}
+
+// NewRecorder returns a recorder that draws cells with the given palette,
+// keeping every frame and playing them back at ten per second.
+func NewRecorder(pal color.Palette) *Recorder {
+	return &Recorder{Palette: pal, Scale: 4, FPS: 10, Every: 1}
+}
+
+// Add records a frame. The recorder keeps the frame, so it mustn't be
+// changed afterward.
+func (r *Recorder) Add(f *Frame) {
+	if r.Every <= 1 || r.added%r.Every == 0 {
+		r.frames = append(r.frames, f)
+		r.pending = nil
+	} else {
+		r.pending = f
+	}
+	r.added++
+}
+
+// Record adds a snapshot of the grid. See Snapshot.
+func Record[T any](r *Recorder, g *geom.Grid[T], sys geom.System, f func(v T, ok bool) uint8) {
+	r.Add(Snapshot(g, sys, f))
+}
+
+// Frames returns the frames that will be written.
+func (r *Recorder) Frames() []*Frame {
+	if r.pending != nil {
+		return append(r.frames[:len(r.frames):len(r.frames)], r.pending)
+	}
+	return r.frames
+}
+
+// The smallest rectangle containing every frame.
+func (r *Recorder) bounds() geom.Rect {
+	frames := r.Frames()
+	b := frames[0].Bounds
+	for _, f := range frames[1:] {
+		if f.Bounds.Lo.X < b.Lo.X {
+			b.Lo.X = f.Bounds.Lo.X
+		}
+		if f.Bounds.Lo.Y < b.Lo.Y {
+			b.Lo.Y = f.Bounds.Lo.Y
+		}
+		if f.Bounds.Hi.X > b.Hi.X {
+			b.Hi.X = f.Bounds.Hi.X
+		}
+		if f.Bounds.Hi.Y > b.Hi.Y {
+			b.Hi.Y = f.Bounds.Hi.Y
+		}
+	}
+	return b
+}
+
+func (r *Recorder) check() error {
+	switch {
+	case len(r.Frames()) == 0:
+		return fmt.Errorf("no frames recorded")
+	case len(r.Palette) == 0 || len(r.Palette) > 256:
+		return fmt.Errorf("palette has %d colors", len(r.Palette))
+	case r.Scale < 1:
+		return fmt.Errorf("bad scale: %d", r.Scale)
+	case r.FPS < 1:
+		return fmt.Errorf("bad frame rate: %d", r.FPS)
+	}
+	for i, f := range r.Frames() {
+		for _, c := range f.Cells {
+			if int(c) >= len(r.Palette) {
+				return fmt.Errorf("frame %d: cell %d isn't in the palette", i, c)
+			}
+		}
+	}
+	return nil
+}
+
+// Draws each frame at the same size, with every frame placed within the
+// bounds of all of them.
+func (r *Recorder) images() []*image.Paletted {
+	b := r.bounds()
+	rect := image.Rect(0, 0, b.Width()*r.Scale, b.Height()*r.Scale)
+	var imgs []*image.Paletted
+	for _, f := range r.Frames() {
+		img := image.NewPaletted(rect, r.Palette)
+		for y := 0; y < rect.Dy(); y++ {
+			for x := 0; x < rect.Dx(); x++ {
+				p := geom.Pt2{X: b.Lo.X + x/r.Scale, Y: b.Lo.Y + y/r.Scale}
+				img.SetColorIndex(x, y, f.At(p))
+			}
+		}
+		imgs = append(imgs, img)
+	}
+	return imgs
+}
+
+// WriteFile writes the frames to path in the format given by its
+// extension: ".gif", ".png" for the last frame only, ".apng", or ".ans"
+// or ".txt" for ANSI text. A path of "-" plays the frames on standard
+// output at the recorder's frame rate.
+func (r *Recorder) WriteFile(path string) error {
+	if err := r.check(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if path == "-" {
+		return r.Play(os.Stdout)
+	}
+	var write func(*os.File) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gif":
+		write = func(f *os.File) error { return r.WriteGIF(f) }
+	case ".png":
+		write = func(f *os.File) error { return r.WritePNG(f) }
+	case ".apng":
+		write = func(f *os.File) error { return r.WriteAPNG(f) }
+	case ".ans", ".txt":
+		write = func(f *os.File) error { return r.WriteANSI(f) }
+	default:
+		return fmt.Errorf("%s: unknown format %q", path, ext)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// Flags defines the -viz and -fps flags on the default flag set, for
+// commands that can visualize their work with WriteFile.
+func Flags() (path *string, fps *int) {
+	path = flag.String("viz", "", "write an animation to this file (.gif, .png, .apng or .ans), or - to play it here")
+	fps = flag.Int("fps", 0, "play the animation at this many frames per second")
+	return path, fps
+}
@@ -0,0 +1,225 @@
+package viz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhconnelly/advent-of-code-2019/geom"
+)
+
+var testPalette = color.Palette{color.Black, color.White, color.RGBA{255, 0, 0, 255}}
+
+// Three frames of a 2x1 strip: a white cell moving right, then a red one
+// appearing below, which grows the canvas.
+func testRecorder() *Recorder {
+	r := NewRecorder(testPalette)
+	r.Scale = 2
+	f := NewFrame(2, 1)
+	f.Set(geom.Pt2{X: 0, Y: 0}, 1)
+	r.Add(f)
+	f = NewFrame(2, 1)
+	f.Set(geom.Pt2{X: 1, Y: 0}, 1)
+	r.Add(f)
+	f = NewFrame(2, 2)
+	f.Set(geom.Pt2{X: 1, Y: 0}, 1)
+	f.Set(geom.Pt2{X: 0, Y: 1}, 2)
+	r.Add(f)
+	return r
+}
+
+func TestSnapshot(t *testing.T) {
+	g := geom.NewSparse[bool]()
+	g.Set(geom.Pt2{X: 0, Y: 0}, true)
+	g.Set(geom.Pt2{X: 1, Y: 1}, false)
+	cell := func(v, ok bool) uint8 {
+		switch {
+		case !ok:
+			return 0
+		case v:
+			return 1
+		}
+		return 2
+	}
+	screen := Snapshot(g, geom.Screen, cell)
+	if want := []uint8{1, 0, 0, 2}; !reflect.DeepEqual(screen.Cells, want) {
+		t.Errorf("screen: got %v, want %v", screen.Cells, want)
+	}
+	cart := Snapshot(g, geom.Cartesian, cell)
+	if want := []uint8{0, 2, 1, 0}; !reflect.DeepEqual(cart.Cells, want) {
+		t.Errorf("cartesian: got %v, want %v", cart.Cells, want)
+	}
+	if want := (geom.Rect{Lo: geom.Pt2{X: 0, Y: -1}, Hi: geom.Pt2{X: 1, Y: 0}}); cart.Bounds != want {
+		t.Errorf("cartesian: bounds %v, want %v", cart.Bounds, want)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	r := NewRecorder(testPalette)
+	r.Every = 3
+	var added []*Frame
+	for i := 0; i < 8; i++ {
+		f := NewFrame(1, 1)
+		added = append(added, f)
+		r.Add(f)
+	}
+	want := []*Frame{added[0], added[3], added[6], added[7]}
+	if got := r.Frames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("kept %d frames, want 0, 3, 6 and 7", len(got))
+	}
+}
+
+func TestGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testRecorder().WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || !reflect.DeepEqual(g.Delay, []int{10, 10, 10}) {
+		t.Fatalf("got %d frames with delays %v", len(g.Image), g.Delay)
+	}
+	last := g.Image[2]
+	if b := last.Bounds(); b.Dx() != 4 || b.Dy() != 4 {
+		t.Errorf("frame is %dx%d, want 4x4", b.Dx(), b.Dy())
+	}
+	for _, tc := range []struct {
+		x, y int
+		c    color.Color
+	}{{0, 0, color.Black}, {3, 1, color.White}, {1, 3, testPalette[2]}} {
+		if got := color.RGBAModel.Convert(last.At(tc.x, tc.y)); got != color.RGBAModel.Convert(tc.c) {
+			t.Errorf("(%d, %d) = %v, want %v", tc.x, tc.y, got, tc.c)
+		}
+	}
+	// the first frame doesn't cover the bottom row
+	if got := g.Image[0].ColorIndexAt(0, 3); got != 0 {
+		t.Errorf("first frame's bottom row is %d, want 0", got)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testRecorder().WritePNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.RGBAModel.Convert(img.At(1, 3)); got != testPalette[2] {
+		t.Errorf("got %v for the red cell", got)
+	}
+}
+
+func TestAPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testRecorder().WriteAPNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+
+	// decoders that don't know about animation see the first frame
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != color.RGBAModel.Convert(color.White) {
+		t.Errorf("first frame starts with %v, want white", got)
+	}
+
+	var types []string
+	var seqs []uint32
+	rest := b[len(pngHeader):]
+	for len(rest) > 0 {
+		n := int(binary.BigEndian.Uint32(rest))
+		typ, data := string(rest[4:8]), rest[8:8+n]
+		if crc := binary.BigEndian.Uint32(rest[8+n:]); crc != crc32.ChecksumIEEE(rest[4:8+n]) {
+			t.Errorf("%s: bad CRC", typ)
+		}
+		switch typ {
+		case "acTL":
+			if frames := binary.BigEndian.Uint32(data); frames != 3 {
+				t.Errorf("acTL has %d frames, want 3", frames)
+			}
+		case "fcTL", "fdAT":
+			seqs = append(seqs, binary.BigEndian.Uint32(data))
+		}
+		types = append(types, typ)
+		rest = rest[12+n:]
+	}
+	got := strings.Join(types, " ")
+	want := "IHDR acTL PLTE fcTL IDAT fcTL fdAT fcTL fdAT IEND"
+	if got != want {
+		t.Errorf("chunks: got %s, want %s", got, want)
+	}
+	if want := []uint32{0, 1, 2, 3, 4}; !reflect.DeepEqual(seqs, want) {
+		t.Errorf("sequence numbers: got %v, want %v", seqs, want)
+	}
+}
+
+func TestANSI(t *testing.T) {
+	r := NewRecorder(testPalette)
+	f := NewFrame(2, 1)
+	f.Set(geom.Pt2{X: 1, Y: 0}, 2)
+	r.Add(f)
+	r.Add(NewFrame(2, 1))
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { sleep = time.Sleep }()
+
+	var buf bytes.Buffer
+	if err := r.WriteANSI(&buf); err != nil {
+		t.Fatal(err)
+	}
+	black, red := "\x1b[48;2;0;0;0m  ", "\x1b[48;2;255;0;0m  "
+	want := "\x1b[2J" +
+		"\x1b[H" + black + red + "\x1b[0m\n" +
+		"\x1b[H" + black + black + "\x1b[0m\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(slept) != 0 {
+		t.Errorf("WriteANSI slept %v", slept)
+	}
+
+	r.FPS = 4
+	buf.Reset()
+	if err := r.Play(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want || !reflect.DeepEqual(slept, []time.Duration{250 * time.Millisecond}) {
+		t.Errorf("Play slept %v and wrote %q", slept, buf.String())
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	r := testRecorder()
+	for _, name := range []string{"out.gif", "out.png", "out.apng", "out.ans"} {
+		path := filepath.Join(dir, name)
+		if err := r.WriteFile(path); err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s: nothing written", name)
+		}
+	}
+	if err := r.WriteFile(filepath.Join(dir, "out.bmp")); err == nil {
+		t.Error("wrote an unknown format")
+	}
+	if err := NewRecorder(testPalette).WriteFile(filepath.Join(dir, "empty.gif")); err == nil {
+		t.Error("wrote an animation with no frames")
+	}
+}